@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// availabilityWriteSlack leaves time to write the matrix once the last lookup ended
+const availabilityWriteSlack = 5 * time.Second
+
+// allCountries are the two letter codes of the countries looked up for
+// countries=all, the App Store storefronts, which Google Play also covers.
+var allCountries = []string{
+	"ae", "ag", "ai", "al", "am", "ao", "ar", "at", "au", "az", "ba", "bb", "bd", "be", "bf", "bg", "bh", "bj",
+	"bm", "bn", "bo", "br", "bs", "bt", "bw", "by", "bz", "ca", "cd", "cg", "ch", "ci", "cl", "cm", "cn", "co",
+	"cr", "cv", "cy", "cz", "de", "dk", "dm", "do", "dz", "ec", "ee", "eg", "es", "fi", "fj", "fm", "fr", "ga",
+	"gb", "gd", "ge", "gh", "gm", "gr", "gt", "gw", "gy", "hk", "hn", "hr", "hu", "id", "ie", "il", "in", "iq",
+	"is", "it", "jm", "jo", "jp", "ke", "kg", "kh", "kn", "kr", "kw", "ky", "kz", "la", "lb", "lc", "lk", "lr",
+	"lt", "lu", "lv", "ly", "ma", "md", "me", "mg", "mk", "ml", "mm", "mn", "mo", "mr", "ms", "mt", "mu", "mv",
+	"mw", "mx", "my", "mz", "na", "ne", "ng", "ni", "nl", "no", "np", "nr", "nz", "om", "pa", "pe", "pg", "ph",
+	"pk", "pl", "pt", "pw", "py", "qa", "ro", "rs", "ru", "rw", "sa", "sb", "sc", "se", "sg", "si", "sk", "sl",
+	"sn", "sr", "st", "sv", "sz", "tc", "td", "th", "tj", "tm", "tn", "to", "tr", "tt", "tw", "tz", "ua", "ug",
+	"us", "uy", "uz", "vc", "ve", "vg", "vn", "vu", "xk", "ye", "za", "zm", "zw",
+}
+
+// countryAvailability is the state of an app in one country.
+// Price is set for every available country, so a free app reports 0.
+type countryAvailability struct {
+	Price     *float64 `json:"price,omitempty"`
+	Country   string   `json:"country"`
+	Version   string   `json:"version,omitempty"`
+	Currency  string   `json:"currency,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	Available bool     `json:"available"`
+}
+
+// availabilityReport is the country availability matrix of an app.
+type availabilityReport struct {
+	Store     string                `json:"store"`
+	ID        string                `json:"id"`
+	Countries []countryAvailability `json:"countries"`
+	// Available and Unavailable list the countries whose lookup succeeded or
+	// found no app; countries whose lookup failed are in neither
+	Available   []string `json:"available"`
+	Unavailable []string `json:"unavailable"`
+}
+
+// handleAvailability looks an app up in several countries at once, at most
+// availabilityConcurrency at a time and through the cache, and reports in
+// which of them the store serves it.
+func (s *server) handleAvailability() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		st, ok := s.registry.Get(query.Get("store"))
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+		if !slices.Contains(st.Options(), "country") {
+			writeInvalidInput(w, r, st.Name(), st.Title()+" does not support countries")
+			return
+		}
+
+		id := query.Get("id")
+		if id == "" {
+			writeInvalidInput(w, r, st.Name(), "Please provide an app id")
+			return
+		}
+		countries, err := parseCountries(query.Get("countries"))
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+		if len(countries) == 0 {
+			writeInvalidInput(w, r, st.Name(), "Please provide the countries to check, or all")
+			return
+		}
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		// Every batch of lookups may take the full timeout, which outlasts the
+		// server's write timeout sized for single lookups
+		batches := (len(countries) + s.cfg.availabilityConcurrency - 1) / s.cfg.availabilityConcurrency
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Duration(batches)*timeout + availabilityWriteSlack))
+
+		refresh := strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache")
+		lang := query.Get("lang")
+
+		report := availabilityReport{
+			Store:       st.Name(),
+			ID:          id,
+			Countries:   make([]countryAvailability, len(countries)),
+			Available:   []string{},
+			Unavailable: []string{},
+		}
+
+		limit := make(chan struct{}, s.cfg.availabilityConcurrency)
+		var wg sync.WaitGroup
+		for i, country := range countries {
+			wg.Go(func() {
+				result := &report.Countries[i]
+				result.Country = country
+
+				select {
+				case limit <- struct{}{}:
+					defer func() { <-limit }()
+				case <-r.Context().Done():
+					result.Error = r.Context().Err().Error()
+					return
+				}
+
+				ctx, cancel := context.WithTimeout(r.Context(), timeout)
+				defer cancel()
+
+				entry, _, err := s.lookup(ctx, st, storeQuery(st, id, lang, country), refresh)
+				switch {
+				case err == nil:
+					result.Available = true
+					result.Version = entry.App.Version
+					result.Price = &entry.App.Price
+					result.Currency = entry.App.Currency
+				case !errors.Is(err, store.ErrAppNotFound):
+					result.Error = err.Error()
+				}
+			})
+		}
+		wg.Wait()
+
+		if err := r.Context().Err(); err != nil {
+			// The client went away; nobody reads the response
+			return
+		}
+
+		for _, result := range report.Countries {
+			switch {
+			case result.Available:
+				report.Available = append(report.Available, result.Country)
+			case result.Error == "":
+				report.Unavailable = append(report.Unavailable, result.Country)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, report)
+	}
+}
+
+// parseCountries parses the countries query parameter, a comma separated list
+// of two letter country codes or "all", into sorted unique lowercase codes.
+func parseCountries(value string) ([]string, error) {
+	if strings.EqualFold(strings.TrimSpace(value), "all") {
+		return slices.Clone(allCountries), nil
+	}
+
+	var countries []string
+	for country := range strings.SplitSeq(value, ",") {
+		country = strings.ToLower(strings.TrimSpace(country))
+		if country == "" {
+			continue
+		}
+		if len(country) != 2 || strings.Trim(country, "abcdefghijklmnopqrstuvwxyz") != "" {
+			return nil, fmt.Errorf("invalid country %q", country)
+		}
+		countries = append(countries, country)
+	}
+
+	slices.Sort(countries)
+	return slices.Compact(countries), nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// regionalStore is an offline store serving an app only in some countries
+type regionalStore struct {
+	fakeStore
+	apps    map[string]store.App
+	errs    map[string]error
+	active  int
+	maxSeen int
+	mu      sync.Mutex
+}
+
+func (r *regionalStore) Lookup(_ context.Context, q store.Query) (store.App, error) {
+	r.mu.Lock()
+	r.active++
+	r.maxSeen = max(r.maxSeen, r.active)
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.active--
+		r.mu.Unlock()
+	}()
+
+	if err, ok := r.errs[q.Country]; ok {
+		return store.App{}, err
+	}
+	if app, ok := r.apps[q.Country]; ok {
+		return app, nil
+	}
+	return store.App{}, store.ErrAppNotFound
+}
+
+func TestAvailabilityHandler(t *testing.T) {
+	fake := &regionalStore{
+		apps: map[string]store.App{
+			"us": {AppID: "1", Version: "2.0"},
+			"de": {AppID: "1", Version: "1.9", Price: 0.99, Currency: "EUR"},
+		},
+		errs: map[string]error{"cn": store.ErrBlocked},
+	}
+	srv := newTestServer(t, fake)
+
+	req := httptest.NewRequest(http.MethodGet, "/availability?store=fake&id=1&countries=US,de,jp,cn,de", http.NoBody)
+	rr := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var report availabilityReport
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+	price, free := 0.99, 0.0
+	assert.Equal(t, availabilityReport{
+		Store: "fake",
+		ID:    "1",
+		Countries: []countryAvailability{
+			{Country: "cn", Error: store.ErrBlocked.Error()},
+			{Country: "de", Available: true, Version: "1.9", Price: &price, Currency: "EUR"},
+			{Country: "jp"},
+			{Country: "us", Available: true, Version: "2.0", Price: &free},
+		},
+		Available:   []string{"de", "us"},
+		Unavailable: []string{"jp"},
+	}, report)
+}
+
+func TestAvailabilityHandlerAllCountries(t *testing.T) {
+	fake := &regionalStore{apps: map[string]store.App{"jp": {AppID: "1", Version: "1.0"}}}
+	srv := newTestServer(t, fake)
+	srv.cfg.availabilityConcurrency = 3
+
+	req := httptest.NewRequest(http.MethodGet, "/availability?store=fake&id=1&countries=all", http.NoBody)
+	rr := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var report availabilityReport
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+	assert.Len(t, report.Countries, len(allCountries))
+	assert.Equal(t, []string{"jp"}, report.Available)
+	assert.Len(t, report.Unavailable, len(allCountries)-1)
+	assert.LessOrEqual(t, fake.maxSeen, 3, "lookups must not exceed the concurrency limit")
+}
+
+func TestAvailabilityHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&id=1&countries=us",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Missing id",
+			query:          "?store=fake&countries=us",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide an app id"},
+		},
+		{
+			name:           "Missing countries",
+			query:          "?store=fake&id=1&countries=,",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide the countries to check, or all"},
+		},
+		{
+			name:           "Invalid country",
+			query:          "?store=fake&id=1&countries=us,usa",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": `invalid country "usa"`},
+		},
+	}
+
+	srv := newTestServer(t, &regionalStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/availability"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+func TestAvailabilityHandlerStoreWithoutCountries(t *testing.T) {
+	srv := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/availability?store=appgallery&id=C123&countries=us", http.NoBody)
+	rr := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rr, req)
+
+	checkResponse(t, rr, http.StatusBadRequest, map[string]string{
+		"code":    codeInvalidInput,
+		"message": "Huawei AppGallery does not support countries",
+	})
+}
+
+func TestParseCountries(t *testing.T) {
+	countries, err := parseCountries(" All ")
+	require.NoError(t, err)
+	assert.Equal(t, allCountries, countries)
+
+	countries, err = parseCountries("jp, US,de,us")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"de", "jp", "us"}, countries)
+
+	_, err = parseCountries("u1")
+	assert.EqualError(t, err, `invalid country "u1"`)
+}
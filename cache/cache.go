@@ -0,0 +1,208 @@
+// Package cache keeps recent store lookups in memory and coalesces concurrent
+// identical lookups into a single fetch. It also remembers the last successful
+// lookup of every key so it can be served when a live fetch fails.
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// sweepInterval is the minimum time between two sweeps of expired entries
+const sweepInterval = time.Minute
+
+// Key identifies a cached lookup.
+type Key struct {
+	Store    string
+	AppID    string
+	BundleID string
+	Lang     string
+	Country  string
+}
+
+// KeyFor builds the cache key of a lookup on the named store.
+func KeyFor(storeName string, q store.Query) Key {
+	return Key{
+		Store:    storeName,
+		AppID:    q.AppID,
+		BundleID: q.BundleID,
+		Lang:     q.Lang,
+		Country:  q.Country,
+	}
+}
+
+// Entry is a cached lookup result.
+type Entry struct {
+	FetchedAt time.Time
+	App       store.App
+}
+
+// Age returns how long ago the entry was fetched.
+func (e Entry) Age(now time.Time) time.Duration {
+	return now.Sub(e.FetchedAt)
+}
+
+// Fetcher performs the actual lookup on a cache miss.
+type Fetcher func(ctx context.Context) (store.App, error)
+
+// call is an in-flight fetch shared by every caller asking for the same key.
+type call struct {
+	started time.Time
+	done    chan struct{}
+	cancel  context.CancelFunc
+	err     error
+	entry   Entry
+	waiters int
+}
+
+// Cache is an in-memory TTL cache of store lookups.
+type Cache struct {
+	now          func() time.Time
+	entries      map[Key]Entry
+	calls        map[Key]*call
+	ttls         map[string]time.Duration
+	lastSweep    time.Time
+	defaultTTL   time.Duration
+	maxStale     time.Duration
+	fetchTimeout time.Duration
+	mu           sync.Mutex
+}
+
+// New creates a cache using defaultTTL for every store without an entry in ttls.
+// A TTL of zero disables caching for that store; concurrent lookups are still coalesced.
+// Entries are kept for maxStale past their TTL so Stale can serve them when a fetch fails.
+// Fetches run for at most fetchTimeout, whatever the deadlines of their callers; zero means no limit.
+func New(defaultTTL time.Duration, ttls map[string]time.Duration, maxStale, fetchTimeout time.Duration) *Cache {
+	return &Cache{
+		now:          time.Now,
+		entries:      make(map[Key]Entry),
+		calls:        make(map[Key]*call),
+		ttls:         ttls,
+		defaultTTL:   defaultTTL,
+		maxStale:     maxStale,
+		fetchTimeout: fetchTimeout,
+	}
+}
+
+// TTL returns how long lookups on the named store are cached.
+func (c *Cache) TTL(storeName string) time.Duration {
+	if ttl, ok := c.ttls[storeName]; ok {
+		return ttl
+	}
+	return c.defaultTTL
+}
+
+// Get returns the cached entry for key when it is still fresh, reporting a hit.
+// Otherwise it fetches the app, sharing the fetch with every concurrent caller
+// of the same key. A refresh always starts a new fetch, which later callers
+// then join. The shared fetch is only cancelled once all of its callers have
+// gone away.
+func (c *Cache) Get(ctx context.Context, key Key, refresh bool, fetch Fetcher) (entry Entry, hit bool, err error) {
+	c.mu.Lock()
+	if !refresh {
+		if e, ok := c.entries[key]; ok && e.Age(c.now()) < c.TTL(key.Store) {
+			c.mu.Unlock()
+			return e, true, nil
+		}
+	}
+
+	cl, ok := c.calls[key]
+	if !ok || refresh {
+		cl = c.startCall(ctx, key, fetch)
+	}
+	cl.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.entry, false, cl.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		cl.waiters--
+		if cl.waiters == 0 {
+			// Nobody waits for the fetch anymore; later callers start a new one
+			cl.cancel()
+			if c.calls[key] == cl {
+				delete(c.calls, key)
+			}
+		}
+		c.mu.Unlock()
+		return Entry{}, false, ctx.Err()
+	}
+}
+
+// Stale returns the last successful entry for key, as long as it is not older
+// than its TTL plus the max-staleness window.
+func (c *Cache) Stale(key Key) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || e.Age(c.now()) >= c.retention(key.Store) {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// retention is how long entries of the named store are kept.
+func (c *Cache) retention(storeName string) time.Duration {
+	return c.TTL(storeName) + c.maxStale
+}
+
+// startCall runs fetch in the background for key. Callers must hold c.mu.
+func (c *Cache) startCall(ctx context.Context, key Key, fetch Fetcher) *call {
+	// The fetch is shared, so it must not end with the caller that started it
+	base := context.WithoutCancel(ctx)
+	fetchCtx, cancel := context.WithCancel(base)
+	if c.fetchTimeout > 0 {
+		cancel()
+		fetchCtx, cancel = context.WithTimeout(base, c.fetchTimeout)
+	}
+
+	cl := &call{started: c.now(), done: make(chan struct{}), cancel: cancel}
+	c.calls[key] = cl
+
+	go func() {
+		defer cancel()
+
+		app, err := fetch(fetchCtx)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		cl.err = err
+		if err == nil {
+			cl.entry = Entry{App: app, FetchedAt: c.now()}
+			// Keep the result of a newer fetch that finished first
+			prev, ok := c.entries[key]
+			if c.retention(key.Store) > 0 && (!ok || !prev.FetchedAt.After(cl.started)) {
+				c.entries[key] = cl.entry
+			}
+			c.sweep()
+		}
+		if c.calls[key] == cl {
+			delete(c.calls, key)
+		}
+		close(cl.done)
+	}()
+
+	return cl
+}
+
+// sweep drops expired entries, at most once per sweepInterval. Callers must hold c.mu.
+func (c *Cache) sweep() {
+	now := c.now()
+	if now.Sub(c.lastSweep) < sweepInterval {
+		return
+	}
+	c.lastSweep = now
+
+	for key, e := range c.entries {
+		if e.Age(now) >= c.retention(key.Store) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+var testKey = Key{Store: "appstore", AppID: "1592213654", Country: "us"}
+
+func TestCacheHitAndMiss(t *testing.T) {
+	c := New(time.Minute, nil, 0, 0)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	fetch := func(context.Context) (store.App, error) {
+		calls.Add(1)
+		return store.App{AppID: "1592213654", Version: "2.0.13"}, nil
+	}
+
+	entry, hit, err := c.Get(context.Background(), testKey, false, fetch)
+	assert.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, "2.0.13", entry.App.Version)
+
+	now = now.Add(30 * time.Second)
+	entry, hit, err = c.Get(context.Background(), testKey, false, fetch)
+	assert.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, 30*time.Second, entry.Age(now))
+
+	_, hit, err = c.Get(context.Background(), testKey, true, fetch)
+	assert.NoError(t, err)
+	assert.False(t, hit, "refresh must bypass the cache")
+
+	now = now.Add(2 * time.Minute)
+	_, hit, err = c.Get(context.Background(), testKey, false, fetch)
+	assert.NoError(t, err)
+	assert.False(t, hit, "expired entries must be refetched")
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestCacheCoalescesConcurrentLookups(t *testing.T) {
+	c := New(time.Minute, nil, 0, 0)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(context.Context) (store.App, error) {
+		calls.Add(1)
+		<-release
+		return store.App{Version: "1.0"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entry, _, err := c.Get(context.Background(), testKey, false, fetch)
+			assert.NoError(t, err)
+			assert.Equal(t, "1.0", entry.App.Version)
+		}()
+	}
+
+	// Let every caller join the in-flight fetch before it completes
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		cl, ok := c.calls[testKey]
+		return ok && cl.waiters == 10
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	c := New(time.Minute, map[string]time.Duration{"playstore": 0}, 0, 0)
+
+	_, _, err := c.Get(context.Background(), testKey, false, func(context.Context) (store.App, error) {
+		return store.App{}, store.ErrAppNotFound
+	})
+	assert.ErrorIs(t, err, store.ErrAppNotFound)
+
+	entry, hit, err := c.Get(context.Background(), testKey, false, func(context.Context) (store.App, error) {
+		return store.App{Version: "1.0"}, nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, "1.0", entry.App.Version)
+
+	// A zero TTL disables caching for that store
+	key := Key{Store: "playstore", BundleID: "com.example"}
+	for range 2 {
+		_, hit, err = c.Get(context.Background(), key, false, func(context.Context) (store.App, error) {
+			return store.App{Version: "1.0"}, nil
+		})
+		assert.NoError(t, err)
+		assert.False(t, hit)
+	}
+}
+
+func TestCacheCancelsFetchWhenAllCallersLeave(t *testing.T) {
+	c := New(time.Minute, nil, 0, 0)
+
+	cancelled := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, _, err := c.Get(ctx, testKey, false, func(fetchCtx context.Context) (store.App, error) {
+		<-fetchCtx.Done()
+		cancelled <- fetchCtx.Err()
+		return store.App{}, errors.New("cancelled")
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	select {
+	case err := <-cancelled:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("fetch was not cancelled")
+	}
+}
+
+func TestCacheRefreshStartsNewFetch(t *testing.T) {
+	c := New(time.Minute, nil, 0, 0)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		entry, _, err := c.Get(context.Background(), testKey, false, func(context.Context) (store.App, error) {
+			close(started)
+			<-release
+			return store.App{Version: "1.0"}, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0", entry.App.Version)
+	}()
+	<-started
+
+	// A refresh must not join the fetch that was already in flight
+	entry, _, err := c.Get(context.Background(), testKey, true, func(context.Context) (store.App, error) {
+		return store.App{Version: "2.0"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", entry.App.Version)
+
+	// The older fetch finishing last does not overwrite the refreshed entry
+	close(release)
+	<-done
+	entry, hit, err := c.Get(context.Background(), testKey, false, nil)
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, "2.0", entry.App.Version)
+}
+
+func TestCacheDetachesFetchFromCallerDeadline(t *testing.T) {
+	c := New(time.Minute, nil, 0, time.Second)
+
+	release := make(chan struct{})
+	fetch := func(fetchCtx context.Context) (store.App, error) {
+		select {
+		case <-release:
+			return store.App{Version: "1.0"}, nil
+		case <-fetchCtx.Done():
+			return store.App{}, fetchCtx.Err()
+		}
+	}
+
+	// The caller that starts the fetch gives up while another one still waits
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, _, err := c.Get(ctx, testKey, false, fetch)
+		first <- err
+	}()
+	second := make(chan Entry, 1)
+	go func() {
+		entry, _, err := c.Get(context.Background(), testKey, false, fetch)
+		assert.NoError(t, err)
+		second <- entry
+	}()
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		cl, ok := c.calls[testKey]
+		return ok && cl.waiters == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(release)
+	assert.Equal(t, "1.0", (<-second).App.Version)
+
+	// Fetches are still bounded by the fetch timeout
+	c = New(time.Minute, nil, 0, 10*time.Millisecond)
+	_, _, err := c.Get(context.Background(), testKey, false, func(fetchCtx context.Context) (store.App, error) {
+		<-fetchCtx.Done()
+		return store.App{}, fetchCtx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCacheStale(t *testing.T) {
+	c := New(time.Minute, nil, time.Hour, 0)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	_, ok := c.Stale(testKey)
+	assert.False(t, ok)
+
+	_, _, err := c.Get(context.Background(), testKey, false, func(context.Context) (store.App, error) {
+		return store.App{Version: "1.0"}, nil
+	})
+	assert.NoError(t, err)
+
+	// Past the TTL the entry is no longer a hit, but still served as stale
+	now = now.Add(30 * time.Minute)
+	_, _, err = c.Get(context.Background(), testKey, false, func(context.Context) (store.App, error) {
+		return store.App{}, errors.New("blocked")
+	})
+	assert.Error(t, err)
+
+	entry, ok := c.Stale(testKey)
+	assert.True(t, ok)
+	assert.Equal(t, "1.0", entry.App.Version)
+	assert.Equal(t, 30*time.Minute, entry.Age(now))
+
+	now = now.Add(time.Hour)
+	_, ok = c.Stale(testKey)
+	assert.False(t, ok, "entries older than TTL plus max staleness must not be served")
+}
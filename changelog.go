@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log"
+	"net/http"
+)
+
+// handleChangelog returns the release notes recorded for each distinct version of an app.
+func (s *server) handleChangelog() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		storeName := query.Get("store")
+		id := query.Get("id")
+
+		if _, ok := s.registry.Get(storeName); !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+		if id == "" {
+			writeInvalidInput(w, r, storeName, "Please provide an app id")
+			return
+		}
+
+		changelog, err := s.db.Changelog(r.Context(), storeName, id)
+		if err != nil {
+			log.Printf("Failed to load changelog: %v", err)
+			writeError(w, r, http.StatusInternalServerError, "Failed to load changelog")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"store":    storeName,
+			"id":       id,
+			"versions": changelog,
+		})
+	}
+}
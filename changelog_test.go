@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestChangelogHandler(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", BundleID: "com.example", Title: "Example", Version: "1.0", ReleaseNotes: "First release."}}
+	srv := newTestServer(t, fake)
+	mux := srv.routes()
+
+	lookup := func() {
+		req := httptest.NewRequest(http.MethodGet, "/fake?appId=1", http.NoBody)
+		req.Header.Set("Cache-Control", "no-cache")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	lookup()
+	fake.app.Version = "1.1"
+	fake.app.ReleaseNotes = "Bug fixes."
+	lookup()
+
+	req := httptest.NewRequest(http.MethodGet, "/changelog?store=fake&id=1", http.NoBody)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Versions []struct {
+			Version      string `json:"version"`
+			ReleaseNotes string `json:"releaseNotes"`
+		} `json:"versions"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	require.Len(t, body.Versions, 2)
+	assert.Equal(t, "1.1", body.Versions[0].Version)
+	assert.Equal(t, "Bug fixes.", body.Versions[0].ReleaseNotes)
+	assert.Equal(t, "First release.", body.Versions[1].ReleaseNotes)
+
+	req = httptest.NewRequest(http.MethodGet, "/changelog?store=unknown&id=1", http.NoBody)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	checkResponse(t, rr, http.StatusBadRequest, map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"})
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+const (
+	// defaultChartLimit is the number of ranks returned without a limit parameter
+	defaultChartLimit = 50
+	// maxChartLimit caps the limit parameter of charts
+	maxChartLimit = 200
+)
+
+// chartEntry is an app at a rank of a chart.
+type chartEntry struct {
+	store.App
+	Rank int `json:"rank"`
+}
+
+// chartRequest holds the validated parameters shared by the chart endpoints.
+type chartRequest struct {
+	store   store.Store
+	charter store.Charter
+	key     storage.ChartKey
+}
+
+// parseChartRequest validates the store, chart, category and country of a chart
+// request, writing the error response and returning false when they are invalid.
+func (s *server) parseChartRequest(w http.ResponseWriter, r *http.Request) (chartRequest, bool) {
+	query := r.URL.Query()
+	st, ok := s.registry.Get(query.Get("store"))
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+		return chartRequest{}, false
+	}
+	charter, ok := st.(store.Charter)
+	if !ok {
+		writeInvalidInput(w, r, st.Name(), st.Title()+" does not support charts")
+		return chartRequest{}, false
+	}
+
+	chart := cmp.Or(query.Get("chart"), store.ChartTopFree)
+	if !slices.Contains(store.Charts, chart) {
+		writeInvalidInput(w, r, st.Name(), "Please provide a valid chart: "+strings.Join(store.Charts, ", "))
+		return chartRequest{}, false
+	}
+
+	return chartRequest{
+		store:   st,
+		charter: charter,
+		key: storage.ChartKey{
+			Store:    st.Name(),
+			Chart:    chart,
+			Category: query.Get("category"),
+			Country:  strings.ToLower(cmp.Or(query.Get("country"), "us")),
+		},
+	}, true
+}
+
+// handleCharts returns the ranked apps of a store's top chart, recording the
+// full ranking as the daily snapshot when snapshot=true.
+func (s *server) handleCharts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		req, ok := s.parseChartRequest(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		limit, err := parseLimit(query.Get("limit"), defaultChartLimit, maxChartLimit)
+		if err != nil {
+			writeInvalidInput(w, r, req.store.Name(), err.Error())
+			return
+		}
+		snapshot, _ := strconv.ParseBool(query.Get("snapshot"))
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+		if err != nil {
+			writeInvalidInput(w, r, req.store.Name(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		// Snapshots replace the day's ranking, so they always hold the full chart
+		fetchLimit := limit
+		if snapshot {
+			fetchLimit = maxChartLimit
+		}
+
+		apps, err := req.charter.Chart(ctx, store.ChartQuery{
+			Chart:    req.key.Chart,
+			Category: req.key.Category,
+			Lang:     query.Get("lang"),
+			Country:  req.key.Country,
+			Limit:    fetchLimit,
+		})
+		if err != nil {
+			writeLookupError(w, r, req.store.Name(), err)
+			return
+		}
+
+		if snapshot {
+			if err := s.db.RecordChart(ctx, req.key, apps, time.Now()); err != nil {
+				log.Printf("Failed to record chart snapshot: %v", err)
+			}
+		}
+		if len(apps) > limit {
+			apps = apps[:limit]
+		}
+
+		entries := make([]chartEntry, 0, len(apps))
+		for i := range apps {
+			entries = append(entries, chartEntry{App: apps[i], Rank: i + 1})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"store":    req.key.Store,
+			"chart":    req.key.Chart,
+			"category": req.key.Category,
+			"country":  req.key.Country,
+			"apps":     entries,
+		})
+	}
+}
+
+// handleChartHistory returns the daily ranks of an app in a chart, from the recorded snapshots.
+func (s *server) handleChartHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		req, ok := s.parseChartRequest(w, r)
+		if !ok {
+			return
+		}
+
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			writeInvalidInput(w, r, req.store.Name(), "Please provide an app id")
+			return
+		}
+
+		ranks, err := s.db.ChartHistory(r.Context(), req.key, id)
+		if err != nil {
+			log.Printf("Failed to load chart history: %v", err)
+			writeError(w, r, http.StatusInternalServerError, "Failed to load chart history")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"store":    req.key.Store,
+			"chart":    req.key.Chart,
+			"category": req.key.Category,
+			"country":  req.key.Country,
+			"id":       id,
+			"ranks":    ranks,
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// fakeChartStore is a fakeStore that also publishes top charts
+type fakeChartStore struct {
+	fakeStore
+	query store.ChartQuery
+	apps  []store.App
+}
+
+func (*fakeChartStore) Name() string { return "charted" }
+
+func (f *fakeChartStore) Chart(_ context.Context, q store.ChartQuery) ([]store.App, error) {
+	f.query = q
+	return f.apps, f.err
+}
+
+func TestChartsHandler(t *testing.T) {
+	fake := &fakeChartStore{apps: []store.App{
+		{AppID: "1", Title: "Radio"},
+		{AppID: "2", Title: "Maps"},
+	}}
+	srv := newTestServer(t, fake, &fakeStore{})
+	mux := srv.routes()
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/charts?store=charted&chart=top-paid&category=6014&country=DE&snapshot=true", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, store.ChartQuery{Chart: "top-paid", Category: "6014", Country: "de", Limit: maxChartLimit}, fake.query,
+		"snapshots must record the full chart")
+
+	var body struct {
+		Chart string `json:"chart"`
+		Apps  []struct {
+			AppID string `json:"appId"`
+			Rank  int    `json:"rank"`
+		} `json:"apps"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, "top-paid", body.Chart)
+	require.Len(t, body.Apps, 2)
+	assert.Equal(t, "2", body.Apps[1].AppID)
+	assert.Equal(t, 2, body.Apps[1].Rank)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/charts/history?store=charted&chart=top-paid&category=6014&country=de&id=2", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var history struct {
+		Ranks []struct {
+			Day  string `json:"day"`
+			Rank int    `json:"rank"`
+		} `json:"ranks"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&history))
+	require.Len(t, history.Ranks, 1)
+	assert.Equal(t, 2, history.Ranks[0].Rank)
+
+	// A shorter response later the same day keeps the full snapshot
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/charts?store=charted&chart=top-paid&category=6014&country=de&limit=1&snapshot=true", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	require.Len(t, body.Apps, 1)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/charts/history?store=charted&chart=top-paid&category=6014&country=de&id=2", http.NoBody))
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&history))
+	require.Len(t, history.Ranks, 1)
+	assert.Equal(t, 2, history.Ranks[0].Rank)
+
+	// Charts are only recorded when asked to
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/charts?store=charted", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, store.ChartTopFree, fake.query.Chart)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/charts/history?store=charted&id=2", http.NoBody))
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&history))
+	assert.Empty(t, history.Ranks)
+}
+
+func TestChartsHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			path:           "/charts?store=unknown",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Store without charts",
+			path:           "/charts?store=fake",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Fake Store does not support charts"},
+		},
+		{
+			name:           "Unknown chart",
+			path:           "/charts?store=charted&chart=top-new",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid chart: top-free, top-paid, top-grossing"},
+		},
+		{
+			name:           "History without id",
+			path:           "/charts/history?store=charted",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide an app id"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeChartStore{}, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
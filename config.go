@@ -0,0 +1,156 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arisecode/katsini/store"
+)
+
+const (
+	// defaultCacheTTL is how long lookups are cached unless configured otherwise
+	defaultCacheTTL = 5 * time.Minute
+	// defaultMaxStaleness is how long past its TTL a lookup may be served when a scrape fails
+	defaultMaxStaleness = 24 * time.Hour
+	// defaultWatchConcurrency is how many watches of one store are checked at once
+	defaultWatchConcurrency = 2
+	// defaultWatchTick is how often the scheduler looks for due watches
+	defaultWatchTick = 10 * time.Second
+	// defaultWebhookAttempts is how often a webhook delivery is tried before it is dead-lettered
+	defaultWebhookAttempts = 5
+	// defaultWebhookBackoff is the wait before the first webhook retry
+	defaultWebhookBackoff = time.Second
+	// defaultMaxWait caps how long /wait holds a request
+	defaultMaxWait = 15 * time.Minute
+	// defaultAvailabilityConcurrency is how many countries /availability looks up at once
+	defaultAvailabilityConcurrency = 4
+)
+
+// config holds the server settings read from the environment
+type config struct {
+	// cacheTTLs overrides cacheTTL per store name
+	cacheTTLs map[string]time.Duration
+	// watchConcurrencies overrides watchConcurrency per store name
+	watchConcurrencies map[string]int
+	// dbPath is the SQLite database file
+	dbPath string
+	// webhookSecret signs webhook payloads
+	webhookSecret string
+	// webhookURLs receive an event whenever a watched app changes
+	webhookURLs []string
+	// pool configures the warm Chrome instances shared by scrapes
+	pool store.PoolConfig
+	// maxTimeout caps how long a single lookup may run, including the timeout query parameter
+	maxTimeout time.Duration
+	// maxWait caps how long /wait holds a request, including its timeout query parameter
+	maxWait time.Duration
+	// cacheTTL is how long lookups are cached
+	cacheTTL time.Duration
+	// maxStaleness is how long past its TTL the last good lookup is served when a scrape fails
+	maxStaleness time.Duration
+	// webhookBackoff is the wait before the first webhook retry, doubled after every attempt
+	webhookBackoff time.Duration
+	// webhookAttempts is how often a webhook delivery is tried before it is dead-lettered
+	webhookAttempts int
+	// watchTick is how often the scheduler looks for due watches
+	watchTick time.Duration
+	// watchConcurrency is how many watches of one store are checked at once
+	watchConcurrency int
+	// availabilityConcurrency is how many countries /availability looks up at once
+	availabilityConcurrency int
+}
+
+// loadConfig reads the server settings from the environment, falling back to defaults
+func loadConfig(registry *store.Registry) config {
+	cfg := config{
+		cacheTTLs:               make(map[string]time.Duration),
+		maxTimeout:              envDuration("MAX_TIMEOUT", store.DefaultTimeout),
+		maxWait:                 envDuration("MAX_WAIT", defaultMaxWait),
+		cacheTTL:                envDuration("CACHE_TTL", defaultCacheTTL),
+		maxStaleness:            envDuration("MAX_STALENESS", defaultMaxStaleness),
+		dbPath:                  envString("DB_PATH", "katsini.db"),
+		watchConcurrencies:      make(map[string]int),
+		watchTick:               envDuration("WATCH_TICK", defaultWatchTick),
+		watchConcurrency:        envInt("WATCH_CONCURRENCY", defaultWatchConcurrency),
+		webhookURLs:             envList("WEBHOOK_URLS"),
+		webhookSecret:           envString("WEBHOOK_SECRET", ""),
+		webhookBackoff:          envDuration("WEBHOOK_BACKOFF", defaultWebhookBackoff),
+		webhookAttempts:         envInt("WEBHOOK_MAX_ATTEMPTS", defaultWebhookAttempts),
+		availabilityConcurrency: envInt("AVAILABILITY_CONCURRENCY", defaultAvailabilityConcurrency),
+	}
+	cfg.pool = store.PoolConfig{
+		Size:    envInt("BROWSER_POOL_SIZE", store.DefaultPoolSize),
+		MaxUses: envInt("BROWSER_MAX_USES", store.DefaultPoolMaxUses),
+	}
+	cfg.pool.MaxTabs = envInt("BROWSER_MAX_TABS", 2*cfg.pool.Size)
+	if cfg.maxTimeout == 0 {
+		cfg.maxTimeout = store.DefaultTimeout
+	}
+
+	// Per-store TTLs, e.g. CACHE_TTL_PLAYSTORE=1h
+	for _, st := range registry.Stores() {
+		key := "CACHE_TTL_" + strings.ToUpper(st.Name())
+		if os.Getenv(key) != "" {
+			cfg.cacheTTLs[st.Name()] = envDuration(key, cfg.cacheTTL)
+		}
+
+		// Per-store watch concurrency, e.g. WATCH_CONCURRENCY_PLAYSTORE=1
+		key = "WATCH_CONCURRENCY_" + strings.ToUpper(st.Name())
+		if os.Getenv(key) != "" {
+			cfg.watchConcurrencies[st.Name()] = envInt(key, cfg.watchConcurrency)
+		}
+	}
+	return cfg
+}
+
+// envString reads a string from the environment
+func envString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envList reads a comma separated list from the environment
+func envList(key string) []string {
+	var list []string
+	for item := range strings.SplitSeq(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// envDuration reads a duration such as "45s" from the environment
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Printf("Invalid %s %q, using default %v", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// envInt reads a positive integer from the environment
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		log.Printf("Invalid %s %q, using default %d", key, value, fallback)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MAX_TIMEOUT", "45s")
+	t.Setenv("CACHE_TTL", "10m")
+	t.Setenv("CACHE_TTL_PLAYSTORE", "1h")
+	t.Setenv("CACHE_TTL_APPGALLERY", "0")
+
+	cfg := loadConfig(store.Default())
+	assert.Equal(t, 45*time.Second, cfg.maxTimeout)
+	assert.Equal(t, 10*time.Minute, cfg.cacheTTL)
+	assert.Equal(t, map[string]time.Duration{"playstore": time.Hour, "appgallery": 0}, cfg.cacheTTLs)
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	t.Setenv("MAX_TIMEOUT", "invalid")
+
+	cfg := loadConfig(store.Default())
+	assert.Equal(t, store.DefaultTimeout, cfg.maxTimeout)
+	assert.Equal(t, defaultCacheTTL, cfg.cacheTTL)
+	assert.Equal(t, defaultMaxWait, cfg.maxWait)
+	assert.Equal(t, defaultAvailabilityConcurrency, cfg.availabilityConcurrency)
+	assert.Empty(t, cfg.cacheTTLs)
+}
+
+func TestLoadConfigBrowserPool(t *testing.T) {
+	t.Setenv("BROWSER_POOL_SIZE", "3")
+	t.Setenv("BROWSER_MAX_USES", "invalid")
+	t.Setenv("BROWSER_MAX_TABS", "")
+
+	cfg := loadConfig(store.Default())
+	assert.Equal(t, store.PoolConfig{Size: 3, MaxUses: store.DefaultPoolMaxUses, MaxTabs: 6}, cfg.pool)
+}
+
+func TestLoadConfigWatchConcurrency(t *testing.T) {
+	t.Setenv("WATCH_CONCURRENCY", "3")
+	t.Setenv("WATCH_CONCURRENCY_PLAYSTORE", "1")
+
+	cfg := loadConfig(store.Default())
+	assert.Equal(t, 3, cfg.watchConcurrency)
+	assert.Equal(t, map[string]int{"playstore": 1}, cfg.watchConcurrencies)
+	assert.Equal(t, defaultWatchTick, cfg.watchTick)
+}
+
+func TestLoadConfigWebhooks(t *testing.T) {
+	t.Setenv("WEBHOOK_URLS", "http://a.example/hook, http://b.example/hook,")
+	t.Setenv("WEBHOOK_SECRET", "secret")
+
+	cfg := loadConfig(store.Default())
+	assert.Equal(t, []string{"http://a.example/hook", "http://b.example/hook"}, cfg.webhookURLs)
+	assert.Equal(t, "secret", cfg.webhookSecret)
+	assert.Equal(t, defaultWebhookAttempts, cfg.webhookAttempts)
+	assert.Equal(t, defaultWebhookBackoff, cfg.webhookBackoff)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// developerLookupConcurrency bounds the lookups filling in the versions
+// missing from developer pages
+const developerLookupConcurrency = 4
+
+// handleDeveloper lists the apps published by a developer.
+func (s *server) handleDeveloper() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		st, ok := s.registry.Get(query.Get("store"))
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+		lister, ok := st.(store.DeveloperLister)
+		if !ok {
+			writeInvalidInput(w, r, st.Name(), st.Title()+" does not support developer listings")
+			return
+		}
+
+		id := query.Get("id")
+		if id == "" {
+			writeInvalidInput(w, r, st.Name(), "Please provide a developer id")
+			return
+		}
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		lang, country := query.Get("lang"), query.Get("country")
+		apps, err := lister.DeveloperApps(ctx, store.DeveloperQuery{ID: id, Lang: lang, Country: country})
+		if err != nil {
+			writeLookupError(w, r, st.Name(), err)
+			return
+		}
+		s.completeVersions(ctx, st, apps, lang, country)
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"store": st.Name(),
+			"id":    id,
+			"apps":  apps,
+		})
+	}
+}
+
+// completeVersions replaces the apps listed without a version by their
+// lookup, going through the cache. Apps whose lookup fails are left as listed.
+func (s *server) completeVersions(ctx context.Context, st store.Store, apps []store.App, lang, country string) {
+	sem := make(chan struct{}, developerLookupConcurrency)
+	var wg sync.WaitGroup
+	for i := range apps {
+		if apps[i].Version != "" {
+			continue
+		}
+
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			id := apps[i].AppID
+			if id == "" {
+				id = apps[i].BundleID
+			}
+			entry, _, err := s.lookup(ctx, st, storeQuery(st, id, lang, country), false)
+			if err != nil {
+				log.Printf("Failed to look up %s %s of developer %s: %v", st.Name(), id, apps[i].DeveloperID, err)
+				return
+			}
+
+			app := entry.App
+			if app.DeveloperID == "" {
+				app.DeveloperID = apps[i].DeveloperID
+			}
+			apps[i] = app
+		})
+	}
+	wg.Wait()
+}
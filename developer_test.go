@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// fakeDeveloperStore is a fakeStore that also lists developer apps
+type fakeDeveloperStore struct {
+	fakeStore
+	apps []store.App
+}
+
+func (*fakeDeveloperStore) Name() string { return "publisher" }
+
+func (f *fakeDeveloperStore) DeveloperApps(_ context.Context, q store.DeveloperQuery) ([]store.App, error) {
+	if q.ID != "42" {
+		return nil, store.ErrAppNotFound
+	}
+	return append([]store.App{}, f.apps...), nil
+}
+
+func TestDeveloperHandler(t *testing.T) {
+	fake := &fakeDeveloperStore{
+		fakeStore: fakeStore{app: store.App{AppID: "2", Title: "Second", Version: "2.0", Developer: "Example"}},
+		apps: []store.App{
+			{AppID: "1", Title: "First", Version: "1.0", DeveloperID: "42"},
+			{AppID: "2", Title: "Second", DeveloperID: "42"},
+		},
+	}
+	srv := newTestServer(t, fake)
+	mux := srv.routes()
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/developer?store=publisher&id=42", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Apps []store.App `json:"apps"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, []store.App{
+		{AppID: "1", Title: "First", Version: "1.0", DeveloperID: "42"},
+		{AppID: "2", Title: "Second", Version: "2.0", Developer: "Example", DeveloperID: "42"},
+	}, body.Apps)
+	assert.Equal(t, 1, fake.calls, "only apps listed without a version are looked up")
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/developer?store=publisher&id=7", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDeveloperHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&id=42",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Store without developer listings",
+			query:          "?store=fake&id=42",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Fake Store does not support developer listings"},
+		},
+		{
+			name:           "Missing id",
+			query:          "?store=publisher",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a developer id"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeDeveloperStore{}, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/developer"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// Error codes of failed lookups; clients branch on these rather than on messages.
+const (
+	codeInvalidInput    = "invalid_input"
+	codeAppNotFound     = "app_not_found"
+	codeUpstreamTimeout = "upstream_timeout"
+	codeUpstreamBlocked = "upstream_blocked"
+	codeUpstreamError   = "upstream_error"
+	codeCanceled        = "canceled"
+	codeInternal        = "internal_error"
+	// Codes of requests that failed outside a store lookup
+	codeNotFound         = "not_found"
+	codeMethodNotAllowed = "method_not_allowed"
+	codeConflict         = "conflict"
+)
+
+// statusClientClosedRequest is the de facto status of requests abandoned by the client
+const statusClientClosedRequest = 499
+
+// apiError is the body of every failed request.
+type apiError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Store     string `json:"store,omitempty"`
+	RequestID string `json:"requestId"`
+	Retryable bool   `json:"retryable"`
+}
+
+// classifyError maps a lookup error to its HTTP status and error code.
+func classifyError(err error) (status int, code string) {
+	switch {
+	case errors.Is(err, store.ErrInvalidInput):
+		return http.StatusBadRequest, codeInvalidInput
+	case errors.Is(err, store.ErrAppNotFound):
+		return http.StatusNotFound, codeAppNotFound
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest, codeCanceled
+	case errors.Is(err, store.ErrPageLoad), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, codeUpstreamTimeout
+	case errors.Is(err, store.ErrBlocked):
+		return http.StatusServiceUnavailable, codeUpstreamBlocked
+	case errors.Is(err, store.ErrUpstream):
+		return http.StatusBadGateway, codeUpstreamError
+	default:
+		return http.StatusInternalServerError, codeInternal
+	}
+}
+
+// writeLookupError writes the structured error response of a failed lookup.
+func writeLookupError(w http.ResponseWriter, r *http.Request, storeName string, err error) {
+	status, code := classifyError(err)
+	writeJSON(w, status, apiError{
+		Code:      code,
+		Message:   err.Error(),
+		Store:     storeName,
+		RequestID: requestID(r.Context()),
+		Retryable: store.Retryable(err),
+	})
+}
+
+// writeInvalidInput writes the structured error response of a malformed lookup request.
+func writeInvalidInput(w http.ResponseWriter, r *http.Request, storeName, message string) {
+	writeJSON(w, http.StatusBadRequest, apiError{
+		Code:      codeInvalidInput,
+		Message:   message,
+		Store:     storeName,
+		RequestID: requestID(r.Context()),
+	})
+}
+
+// writeError writes the structured error response of a request that failed
+// outside a store lookup, such as an unknown store or a missing watch.
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	writeJSON(w, status, apiError{
+		Code:      statusCode(status),
+		Message:   message,
+		RequestID: requestID(r.Context()),
+	})
+}
+
+// statusCode returns the error code of a status written by writeError.
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return codeInvalidInput
+	case http.StatusNotFound:
+		return codeNotFound
+	case http.StatusMethodNotAllowed:
+		return codeMethodNotAllowed
+	case http.StatusConflict:
+		return codeConflict
+	default:
+		return codeInternal
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		err            error
+		expectedCode   string
+		expectedStatus int
+	}{
+		{fmt.Errorf("%w: malformed appId", store.ErrInvalidInput), codeInvalidInput, http.StatusBadRequest},
+		{store.ErrAppNotFound, codeAppNotFound, http.StatusNotFound},
+		{fmt.Errorf("%w: timeout while extracting data", store.ErrPageLoad), codeUpstreamTimeout, http.StatusGatewayTimeout},
+		{fmt.Errorf("failed to get app: %w", context.DeadlineExceeded), codeUpstreamTimeout, http.StatusGatewayTimeout},
+		{fmt.Errorf("%w: status 429", store.ErrBlocked), codeUpstreamBlocked, http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: status 500", store.ErrUpstream), codeUpstreamError, http.StatusBadGateway},
+		{context.Canceled, codeCanceled, statusClientClosedRequest},
+		{errors.New("browser crashed"), codeInternal, http.StatusInternalServerError},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.expectedCode, func(t *testing.T) {
+			status, code := classifyError(tt.err)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
+
+func TestLookupErrorResponse(t *testing.T) {
+	fake := &fakeStore{err: fmt.Errorf("%w: status 503", store.ErrUpstream)}
+	handler := requestIDMiddleware(newTestServer(t, fake).routes())
+
+	req := httptest.NewRequest(http.MethodGet, "/fake?appId=1", http.NoBody)
+	req.Header.Set("X-Request-ID", "abc123")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadGateway, rr.Code)
+	assert.Equal(t, "abc123", rr.Header().Get("X-Request-ID"))
+
+	var body apiError
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, apiError{
+		Code:      codeUpstreamError,
+		Message:   "store error: status 503",
+		Store:     "fake",
+		RequestID: "abc123",
+		Retryable: true,
+	}, body)
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := requestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = requestID(r.Context())
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Len(t, seen, 16)
+	assert.Equal(t, seen, rr.Header().Get("X-Request-ID"))
+}
+
+func TestErrorResponse(t *testing.T) {
+	handler := requestIDMiddleware(newTestServer(t).routes())
+
+	req := httptest.NewRequest(http.MethodGet, "/history?store=unknown&id=1", http.NoBody)
+	req.Header.Set("X-Request-ID", "abc123")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var body apiError
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, apiError{
+		Code:      codeInvalidInput,
+		Message:   "Please provide a valid store",
+		RequestID: "abc123",
+	}, body)
+}
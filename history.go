@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log"
+	"net/http"
+)
+
+// handleHistory returns the distinct versions katsini has seen for an app.
+func (s *server) handleHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		storeName := query.Get("store")
+		id := query.Get("id")
+
+		if _, ok := s.registry.Get(storeName); !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+		if id == "" {
+			writeInvalidInput(w, r, storeName, "Please provide an app id")
+			return
+		}
+
+		history, err := s.db.History(r.Context(), storeName, id)
+		if err != nil {
+			log.Printf("Failed to load history: %v", err)
+			writeError(w, r, http.StatusInternalServerError, "Failed to load history")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"store":    storeName,
+			"id":       id,
+			"versions": history,
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestHistoryHandler(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", BundleID: "com.example", Title: "Example", Version: "1.0"}}
+	srv := newTestServer(t, fake)
+	mux := srv.routes()
+
+	lookup := func() {
+		req := httptest.NewRequest(http.MethodGet, "/fake?appId=1", http.NoBody)
+		req.Header.Set("Cache-Control", "no-cache")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	lookup()
+	lookup()
+	fake.app.Version = "1.1"
+	lookup()
+
+	req := httptest.NewRequest(http.MethodGet, "/history?store=fake&id=com.example", http.NoBody)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Versions []struct {
+			Version   string `json:"version"`
+			FirstSeen string `json:"firstSeen"`
+			LastSeen  string `json:"lastSeen"`
+		} `json:"versions"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	require.Len(t, body.Versions, 2)
+	assert.Equal(t, "1.0", body.Versions[0].Version)
+	assert.Equal(t, "1.1", body.Versions[1].Version)
+	assert.NotEmpty(t, body.Versions[0].FirstSeen)
+}
+
+func TestHistoryHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&id=1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Missing id",
+			query:          "?store=fake",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Please provide an app id"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/history"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
@@ -2,14 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+
+	"github.com/arisecode/katsini/cache"
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+	"github.com/arisecode/katsini/watch"
+	"github.com/arisecode/katsini/webhook"
 )
 
 // ResponseWriter helper to standardize JSON responses
@@ -21,137 +32,361 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	}
 }
 
-// ErrorResponse helper for consistent error responses
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"error": message})
-}
-
 // Middleware for logging
 func loggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s", r.Method, r.URL.Path)
+		log.Printf("%s %s [%s]", r.Method, r.URL.Path, requestID(r.Context()))
 		next.ServeHTTP(w, r)
 	})
 }
 
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// maxRequestIDLength bounds request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// Middleware assigning every request an ID, reusing a client provided X-Request-ID
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// newRequestID returns a random request ID
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestID returns the ID of the request ctx belongs to
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
 // Middleware for panic recovery
 func recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
 				log.Printf("Panic recovered: %v", err)
-				writeError(w, http.StatusInternalServerError, "Internal server error")
+				writeError(w, r, http.StatusInternalServerError, "Internal server error")
 			}
 		}()
 		next.ServeHTTP(w, r)
 	})
 }
 
-func handleGooglePlayStore(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
+// server holds the state shared by the HTTP handlers.
+type server struct {
+	registry *store.Registry
+	pool     *store.BrowserPool
+	cache    *cache.Cache
+	db       *storage.DB
+	watcher  *watch.Scheduler
+	webhooks *webhook.Dispatcher
+	metrics  *lookupMetrics
+	waits    *pollers
+	cfg      config
+}
+
+// newServer creates a server for the given stores, sharing the default browser pool.
+func newServer(cfg config, registry *store.Registry, db *storage.DB) *server {
+	s := &server{
+		registry: registry,
+		pool:     store.DefaultBrowserPool(),
+		cache:    cache.New(cfg.cacheTTL, cfg.cacheTTLs, cfg.maxStaleness, cfg.maxTimeout),
+		db:       db,
+		webhooks: webhook.New(db, webhook.Config{
+			URLs:        cfg.webhookURLs,
+			Secret:      cfg.webhookSecret,
+			Backoff:     cfg.webhookBackoff,
+			MaxAttempts: cfg.webhookAttempts,
+		}),
+		metrics: newLookupMetrics(),
+		waits:   newPollers(waitBackoff, maxWaitBackoff),
+		cfg:     cfg,
 	}
+	s.watcher = watch.New(db, s.checkWatch, watch.Config{
+		StoreConcurrency: cfg.watchConcurrencies,
+		OnChange:         s.notifyChange,
+		Tick:             cfg.watchTick,
+		Concurrency:      cfg.watchConcurrency,
+		Jitter:           watchJitter,
+	})
+	return s
+}
 
-	query := r.URL.Query()
-	bundleID := query.Get("bundleId")
-	lang := query.Get("lang")
-	country := query.Get("country")
+// lookup fetches an app through the cache. Every live fetch is recorded as a snapshot.
+func (s *server) lookup(ctx context.Context, st store.Store, q store.Query, refresh bool) (cache.Entry, bool, error) {
+	return s.cache.Get(ctx, cache.KeyFor(st.Name(), q), refresh, func(ctx context.Context) (store.App, error) {
+		app, err := st.Lookup(ctx, q)
+		s.metrics.record(st.Name(), err)
+		if err != nil {
+			return store.App{}, err
+		}
 
-	if bundleID == "" {
-		writeError(w, http.StatusBadRequest, "Please provide an app bundleId")
-		return
+		if err := s.db.RecordSnapshot(ctx, st.Name(), app, time.Now()); err != nil {
+			log.Printf("Failed to record snapshot: %v", err)
+		}
+		return app, nil
+	})
+}
+
+// handleLookup serves app lookups for a single store.
+func (s *server) handleLookup(st store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		q := store.Query{
+			AppID:    query.Get(string(store.IdentifierAppID)),
+			BundleID: query.Get(string(store.IdentifierBundleID)),
+			Lang:     query.Get("lang"),
+			Country:  query.Get("country"),
+		}
+
+		if !hasIdentifier(st, q) {
+			writeInvalidInput(w, r, st.Name(), "Please provide an app "+identifierList(st))
+			return
+		}
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		// Cache-Control: no-cache forces a fresh scrape
+		refresh := strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache")
+		entry, hit, err := s.lookup(ctx, st, q, refresh)
+		if err != nil {
+			// Serve the last known good answer rather than failing the caller
+			if stale, ok := s.staleEntry(cache.KeyFor(st.Name(), q), err); ok {
+				log.Printf("Serving stale %s data for %+v: %v", st.Name(), q, err)
+				w.Header().Set("X-Cache", "STALE")
+				w.Header().Set("Age", strconv.Itoa(int(stale.Age(time.Now()).Seconds())))
+				writeJSON(w, http.StatusOK, staleResponse{
+					App:   stale.App,
+					Stale: true,
+					Age:   int(stale.Age(time.Now()).Seconds()),
+					Error: err.Error(),
+				})
+				return
+			}
+			writeLookupError(w, r, st.Name(), err)
+			return
+		}
+
+		writeCacheHeaders(w, entry, hit)
+		writeJSON(w, http.StatusOK, entry.App)
+	}
+}
+
+// staleResponse is a last known good app served because the live lookup failed.
+type staleResponse struct {
+	Error string `json:"error"`
+	store.App
+	Age   int  `json:"age"`
+	Stale bool `json:"stale"`
+}
+
+// staleEntry returns the last known good entry for a failed lookup. Missing
+// apps, invalid queries and lookups abandoned by the client are never answered
+// from stale data.
+func (s *server) staleEntry(key cache.Key, err error) (cache.Entry, bool) {
+	if !store.Retryable(err) || errors.Is(err, context.Canceled) {
+		return cache.Entry{}, false
 	}
+	return s.cache.Stale(key)
+}
 
-	app, err := GooglePlayStore(bundleID, lang, country)
+// writeCacheHeaders reports whether a lookup was served from the cache and how old it is.
+func writeCacheHeaders(w http.ResponseWriter, entry cache.Entry, hit bool) {
+	if hit {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
+	w.Header().Set("Age", strconv.Itoa(int(entry.Age(time.Now()).Seconds())))
+}
+
+// lookupTimeout parses the optional timeout query parameter, given either as a
+// duration ("10s") or in whole seconds, and caps it at maxTimeout.
+func lookupTimeout(value string, maxTimeout time.Duration) (time.Duration, error) {
+	if value == "" {
+		return maxTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
-		return
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid timeout %q", value)
+		}
+		timeout = time.Duration(seconds) * time.Second
 	}
 
-	writeJSON(w, http.StatusOK, map[string]string{
-		"bundleId":  app.bundleID,
-		"url":       app.url,
-		"title":     app.title,
-		"version":   app.version,
-		"updated":   app.updated,
-		"developer": app.developer,
-	})
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q", value)
+	}
+	return min(timeout, maxTimeout), nil
 }
 
-func handleAppleAppStore(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
+// hasIdentifier reports whether the query sets at least one identifier accepted by the store.
+func hasIdentifier(s store.Store, q store.Query) bool {
+	for _, id := range s.Identifiers() {
+		if q.ID(id) != "" {
+			return true
+		}
 	}
+	return false
+}
 
-	query := r.URL.Query()
-	appID := query.Get("appId")
-	bundleID := query.Get("bundleId")
-	country := query.Get("country")
+// storeQuery builds a lookup for a single id, passed as the app ID or bundle ID
+// depending on what the store accepts. Stores accepting both get numeric ids as
+// app IDs, like the App Store's track IDs.
+func storeQuery(st store.Store, id, lang, country string) store.Query {
+	q := store.Query{Lang: lang, Country: country}
 
-	if appID == "" && bundleID == "" {
-		writeError(w, http.StatusBadRequest, "Please provide an app appId or bundleId")
-		return
+	ids := st.Identifiers()
+	identifier := ids[0]
+	if len(ids) > 1 {
+		identifier = store.IdentifierBundleID
+		if _, err := strconv.ParseUint(id, 10, 64); err == nil {
+			identifier = store.IdentifierAppID
+		}
 	}
 
-	app, err := AppleAppStore(appID, bundleID, country)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	writeJSON(w, http.StatusOK, map[string]string{
-		"appId":     app.appID,
-		"bundleId":  app.bundleID,
-		"url":       app.url,
-		"title":     app.title,
-		"version":   app.version,
-		"updated":   app.updated,
-		"developer": app.developer,
-	})
+	if identifier == store.IdentifierAppID {
+		q.AppID = id
+	} else {
+		q.BundleID = id
+	}
+	return q
+}
+
+// identifierList joins the identifiers accepted by a store for use in messages.
+func identifierList(s store.Store) string {
+	ids := make([]string, 0, len(s.Identifiers()))
+	for _, id := range s.Identifiers() {
+		ids = append(ids, string(id))
+	}
+	return strings.Join(ids, " or ")
+}
+
+// storeInfo describes a registered store in the /stores listing.
+type storeInfo struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Path        string   `json:"path"`
+	Identifiers []string `json:"identifiers"`
+	Options     []string `json:"options"`
 }
 
-func handleHuaweiAppGallery(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-		return
+// handleStores lists the registered stores and the query parameters each accepts.
+func (s *server) handleStores() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		stores := make([]storeInfo, 0, len(s.registry.Stores()))
+		for _, st := range s.registry.Stores() {
+			info := storeInfo{
+				Name:        st.Name(),
+				Title:       st.Title(),
+				Path:        "/" + st.Name(),
+				Identifiers: make([]string, 0, len(st.Identifiers())),
+				Options:     append([]string{}, st.Options()...),
+			}
+			for _, id := range st.Identifiers() {
+				info.Identifiers = append(info.Identifiers, string(id))
+			}
+			stores = append(stores, info)
+		}
+
+		writeJSON(w, http.StatusOK, stores)
 	}
+}
 
-	appID := r.URL.Query().Get("appId")
-	if appID == "" {
-		writeError(w, http.StatusBadRequest, "Please provide an app appId")
-		return
+// handlePool reports the browser pool statistics.
+func (s *server) handlePool() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, s.pool.Stats())
 	}
+}
 
-	app, err := HuaweiAppGallery(appID)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	writeJSON(w, http.StatusOK, map[string]string{
-		"appId":     app.appID,
-		"bundleId":  app.bundleID,
-		"url":       app.url,
-		"title":     app.title,
-		"version":   app.version,
-		"updated":   app.updated,
-		"developer": app.developer,
-	})
+// routes mounts one lookup route per registered store plus the store listing.
+func (s *server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, st := range s.registry.Stores() {
+		mux.HandleFunc("/"+st.Name(), s.handleLookup(st))
+	}
+	mux.HandleFunc("/stores", s.handleStores())
+	mux.HandleFunc("/pool", s.handlePool())
+	mux.HandleFunc("/metrics", s.handleMetrics())
+	mux.HandleFunc("/history", s.handleHistory())
+	mux.HandleFunc("/changelog", s.handleChangelog())
+	mux.HandleFunc("/ratings/history", s.handleRatingHistory())
+	mux.HandleFunc("/search", s.handleSearch())
+	mux.HandleFunc("/developer", s.handleDeveloper())
+	mux.HandleFunc("/charts", s.handleCharts())
+	mux.HandleFunc("/charts/history", s.handleChartHistory())
+	mux.HandleFunc("/reviews", s.handleReviews())
+	mux.HandleFunc("/products", s.handleProducts())
+	mux.HandleFunc("/products/{name}", s.handleProduct())
+	mux.HandleFunc("/update-check", s.handleUpdateCheck())
+	mux.HandleFunc("/wait", s.handleWait())
+	mux.HandleFunc("/availability", s.handleAvailability())
+	mux.HandleFunc("/watches", s.handleWatches())
+	mux.HandleFunc("/webhooks/dead-letters", s.handleDeadLetters())
+	return mux
 }
 
 func main() {
-	// Create a new mux router
-	mux := http.NewServeMux()
+	registry := store.Default()
+	cfg := loadConfig(registry)
+	store.ConfigureDefaultBrowserPool(cfg.pool)
 
-	// Register routes
-	mux.HandleFunc("/playstore", handleGooglePlayStore)
-	mux.HandleFunc("/appstore", handleAppleAppStore)
-	mux.HandleFunc("/appgallery", handleHuaweiAppGallery)
+	db, err := storage.Open(context.Background(), cfg.dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Create a new mux router with one route per registered store
+	app := newServer(cfg, registry, db)
+	mux := app.routes()
+
+	// Start the warm browsers in the background so the first lookups do not pay for it
+	go func() {
+		if err := app.pool.Warm(); err != nil {
+			log.Printf("Failed to warm browser pool: %v", err)
+		}
+	}()
 
 	// Apply middleware
-	handler := loggerMiddleware(recoveryMiddleware(mux))
+	handler := requestIDMiddleware(loggerMiddleware(recoveryMiddleware(mux)))
 
 	// Start server
 	log.Println("Server starting on :8080")
@@ -160,7 +395,7 @@ func main() {
 		Handler:           handler,
 		ReadTimeout:       30 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
-		WriteTimeout:      30 * time.Second,
+		WriteTimeout:      cfg.maxTimeout + 5*time.Second,
 		IdleTimeout:       60 * time.Second,
 		MaxHeaderBytes:    1 << 20, // 1MB
 	}
@@ -170,6 +405,13 @@ func main() {
 	ctx, stop := signal.NotifyContext(baseCtx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// Check watched apps until shutdown
+	watcherDone := make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		app.watcher.Run(ctx)
+	}()
+
 	// Start server
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -187,6 +429,9 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	<-watcherDone
+	app.webhooks.Close()
+	app.pool.Close()
 
 	log.Println("Server exited properly")
 }
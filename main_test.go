@@ -3,68 +3,23 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arisecode/katsini/internal/chrometest"
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
 )
 
 func TestMain(m *testing.M) {
-	ctx := context.Background()
-
-	// Create Chrome container for testing
-	req := testcontainers.ContainerRequest{
-		Image:        "chromedp/headless-shell:136.0.7052.2",
-		ExposedPorts: []string{"9222/tcp"},
-		WaitingFor: wait.ForAll(
-			wait.NewHTTPStrategy("/json/version").WithPort("9222/tcp").WithStatusCodeMatcher(func(status int) bool {
-				return status == 200
-			}),
-		),
-		Cmd: []string{
-			"--no-sandbox",
-			"--disable-gpu",
-			"--remote-debugging-address=0.0.0.0",
-			"--remote-debugging-port=9222",
-			"--disable-extensions",
-			"--enable-automation",
-			"--disable-blink-features=AutomationControlled",
-			"--incognito",
-		},
-	}
-
-	chromeContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	if err != nil {
-		log.Panicf("Failed to start Chrome container: %v", err)
-	}
-
-	defer func() {
-		if err := chromeContainer.Terminate(ctx); err != nil {
-			log.Panicf("Failed to terminate Chrome container: %v", err)
-		}
-	}()
-
-	host, err := chromeContainer.Host(ctx)
-	if err != nil {
-		log.Panicf("Failed to get Chrome host: %v", err)
-	}
-
-	port, err := chromeContainer.MappedPort(ctx, "9222/tcp")
-	if err != nil {
-		log.Panicf("Failed to get Chrome port: %v", err)
-	}
-
-	_ = os.Setenv("CHROME_HOST", host)
-	_ = os.Setenv("CHROME_PORT", port.Port())
-
-	m.Run()
+	chrometest.Main(m)
 }
 
 func TestGooglePlayStoreHandler(t *testing.T) {
@@ -95,7 +50,9 @@ func TestGooglePlayStoreHandler(t *testing.T) {
 			query:          "?lang=en&country=US",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]string{
-				"error": "Please provide an app bundleId",
+				"code":    "invalid_input",
+				"message": "Please provide an app bundleId",
+				"store":   "playstore",
 			},
 		},
 		{
@@ -104,7 +61,19 @@ func TestGooglePlayStoreHandler(t *testing.T) {
 			query:          "?bundleId=invalid&lang=en&country=US",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]string{
-				"error": "app not found",
+				"code":    "invalid_input",
+				"message": `invalid input: malformed bundleId "invalid"`,
+			},
+		},
+		{
+			name:           "Unknown bundleId",
+			method:         http.MethodGet,
+			query:          "?bundleId=com.arisecode.katsini.missing&lang=en&country=US",
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]string{
+				"code":      "app_not_found",
+				"message":   "app not found",
+				"retryable": "false",
 			},
 		},
 		{
@@ -113,7 +82,8 @@ func TestGooglePlayStoreHandler(t *testing.T) {
 			query:          "?bundleId=com.test.app",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody: map[string]string{
-				"error": "Method not allowed",
+				"code":    codeMethodNotAllowed,
+				"message": "Method not allowed",
 			},
 		},
 	}
@@ -123,7 +93,7 @@ func TestGooglePlayStoreHandler(t *testing.T) {
 			req := httptest.NewRequest(tt.method, "/playstore"+tt.query, http.NoBody)
 			rr := httptest.NewRecorder()
 
-			handler := http.HandlerFunc(handleGooglePlayStore)
+			handler := newTestServer(t).handleLookup(store.PlayStore{})
 			handler.ServeHTTP(rr, req)
 
 			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
@@ -160,7 +130,9 @@ func TestAppleAppStoreHandler(t *testing.T) {
 			query:          "?country=US",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]string{
-				"error": "Please provide an app appId or bundleId",
+				"code":    "invalid_input",
+				"message": "Please provide an app appId or bundleId",
+				"store":   "appstore",
 			},
 		},
 		{
@@ -169,7 +141,19 @@ func TestAppleAppStoreHandler(t *testing.T) {
 			query:          "?appId=invalid&country=US",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]string{
-				"error": "app not found",
+				"code":    "invalid_input",
+				"message": `invalid input: malformed appId "invalid"`,
+			},
+		},
+		{
+			name:           "Unknown appId",
+			method:         http.MethodGet,
+			query:          "?appId=1&country=US",
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]string{
+				"code":      "app_not_found",
+				"message":   "app not found",
+				"retryable": "false",
 			},
 		},
 		{
@@ -178,7 +162,8 @@ func TestAppleAppStoreHandler(t *testing.T) {
 			query:          "?appId=com.test.app",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody: map[string]string{
-				"error": "Method not allowed",
+				"code":    codeMethodNotAllowed,
+				"message": "Method not allowed",
 			},
 		},
 	}
@@ -188,7 +173,7 @@ func TestAppleAppStoreHandler(t *testing.T) {
 			req := httptest.NewRequest(tt.method, "/appstore"+tt.query, http.NoBody)
 			rr := httptest.NewRecorder()
 
-			handler := http.HandlerFunc(handleAppleAppStore)
+			handler := newTestServer(t).handleLookup(store.AppStore{})
 			handler.ServeHTTP(rr, req)
 
 			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
@@ -225,7 +210,9 @@ func TestHuaweiAppGalleryHandler(t *testing.T) {
 			query:          "",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]string{
-				"error": "Please provide an app appId",
+				"code":    "invalid_input",
+				"message": "Please provide an app appId",
+				"store":   "appgallery",
 			},
 		},
 		{
@@ -234,7 +221,8 @@ func TestHuaweiAppGalleryHandler(t *testing.T) {
 			query:          "?appId=invalid",
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: map[string]string{
-				"error": "app not found",
+				"code":    "invalid_input",
+				"message": `invalid input: malformed appId "invalid"`,
 			},
 		},
 		{
@@ -243,7 +231,8 @@ func TestHuaweiAppGalleryHandler(t *testing.T) {
 			query:          "?appId=1234562123",
 			expectedStatus: http.StatusMethodNotAllowed,
 			expectedBody: map[string]string{
-				"error": "Method not allowed",
+				"code":    codeMethodNotAllowed,
+				"message": "Method not allowed",
 			},
 		},
 	}
@@ -253,7 +242,7 @@ func TestHuaweiAppGalleryHandler(t *testing.T) {
 			req := httptest.NewRequest(tt.method, "/huawei"+tt.query, http.NoBody)
 			rr := httptest.NewRecorder()
 
-			handler := http.HandlerFunc(handleHuaweiAppGallery)
+			handler := newTestServer(t).handleLookup(store.AppGallery{})
 			handler.ServeHTTP(rr, req)
 
 			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
@@ -261,6 +250,178 @@ func TestHuaweiAppGalleryHandler(t *testing.T) {
 	}
 }
 
+func TestStoresHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/stores", http.NoBody)
+	rr := httptest.NewRecorder()
+
+	newTestServer(t).routes().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var stores []storeInfo
+	if err := json.NewDecoder(rr.Body).Decode(&stores); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+
+	expected := []storeInfo{
+		{Name: "playstore", Title: "Google Play Store", Path: "/playstore", Identifiers: []string{"bundleId"}, Options: []string{"lang", "country"}},
+		{Name: "appstore", Title: "Apple App Store", Path: "/appstore", Identifiers: []string{"appId", "bundleId"}, Options: []string{"country"}},
+		{Name: "appgallery", Title: "Huawei AppGallery", Path: "/appgallery", Identifiers: []string{"appId"}, Options: []string{}},
+	}
+	assert.Equal(t, expected, stores)
+}
+
+func TestLookupCache(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", BundleID: "com.example", Version: "1.0"}}
+	srv := newTestServer(t)
+
+	lookup := func(header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/fake?appId=1", http.NoBody)
+		if header != "" {
+			req.Header.Set("Cache-Control", header)
+		}
+		rr := httptest.NewRecorder()
+		srv.handleLookup(fake).ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := lookup("")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "MISS", rr.Header().Get("X-Cache"))
+	assert.Equal(t, "0", rr.Header().Get("Age"))
+
+	rr = lookup("")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "HIT", rr.Header().Get("X-Cache"))
+
+	rr = lookup("no-cache")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "MISS", rr.Header().Get("X-Cache"))
+	assert.Equal(t, 2, fake.calls)
+}
+
+func TestLookupServesStaleOnError(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", BundleID: "com.example", Version: "1.0"}}
+	srv := newTestServer(t)
+
+	lookup := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/fake?appId=1", http.NoBody)
+		req.Header.Set("Cache-Control", "no-cache")
+		rr := httptest.NewRecorder()
+		srv.handleLookup(fake).ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, lookup().Code)
+
+	fake.err = errors.New("failed to extract app data")
+	rr := lookup()
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "STALE", rr.Header().Get("X-Cache"))
+
+	var body map[string]any
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, true, body["stale"])
+	assert.Equal(t, "1.0", body["version"])
+	assert.Equal(t, "failed to extract app data", body["error"])
+	assert.Contains(t, body, "age")
+
+	// A missing app is reported even when stale data exists
+	fake.err = store.ErrAppNotFound
+	assert.Equal(t, http.StatusNotFound, lookup().Code)
+}
+
+func TestPoolHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/pool", http.NoBody)
+	rr := httptest.NewRecorder()
+
+	newTestServer(t).routes().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var stats store.PoolStats
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&stats))
+	assert.Positive(t, stats.Size)
+	assert.GreaterOrEqual(t, stats.MaxTabs, stats.Size)
+}
+
+func TestLookupTimeout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "Default", value: "", expected: 30 * time.Second},
+		{name: "Duration", value: "10s", expected: 10 * time.Second},
+		{name: "Seconds", value: "5", expected: 5 * time.Second},
+		{name: "Capped", value: "2m", expected: 30 * time.Second},
+		{name: "Negative", value: "-1s", wantErr: true},
+		{name: "Invalid", value: "soon", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			timeout, err := lookupTimeout(tc.value, 30*time.Second)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, timeout)
+		})
+	}
+}
+
+// fakeStore is an offline store returning a fixed app or error
+type fakeStore struct {
+	err   error
+	app   store.App
+	calls int
+}
+
+func (*fakeStore) Name() string { return "fake" }
+
+func (*fakeStore) Title() string { return "Fake Store" }
+
+func (*fakeStore) Identifiers() []store.Identifier {
+	return []store.Identifier{store.IdentifierAppID}
+}
+
+func (*fakeStore) Options() []string { return []string{"country"} }
+
+func (f *fakeStore) Lookup(_ context.Context, _ store.Query) (store.App, error) {
+	f.calls++
+	return f.app, f.err
+}
+
+// newTestServer creates a server with the default config and an empty database,
+// serving the given stores or every supported store when none are given
+func newTestServer(t *testing.T, stores ...store.Store) *server {
+	t.Helper()
+
+	registry := store.Default()
+	if len(stores) > 0 {
+		registry = store.NewRegistry(stores...)
+	}
+
+	db, err := storage.Open(context.Background(), filepath.Join(t.TempDir(), "katsini.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return newServer(config{
+		maxTimeout:              store.DefaultTimeout,
+		cacheTTL:                defaultCacheTTL,
+		maxStaleness:            defaultMaxStaleness,
+		watchConcurrency:        defaultWatchConcurrency,
+		availabilityConcurrency: defaultAvailabilityConcurrency,
+	}, registry, db)
+}
+
 // Helper function to check response status and body
 func checkResponse(t *testing.T, response *httptest.ResponseRecorder, expectedStatus int, expectedBody map[string]string) {
 	t.Helper()
@@ -270,13 +431,13 @@ func checkResponse(t *testing.T, response *httptest.ResponseRecorder, expectedSt
 	}
 
 	if expectedBody != nil {
-		var actualBody map[string]string
+		var actualBody map[string]any
 		if err := json.NewDecoder(response.Body).Decode(&actualBody); err != nil {
 			t.Fatalf("Failed to decode response body: %v", err)
 		}
 
 		for key, expectedValue := range expectedBody {
-			if actualValue, exists := actualBody[key]; !exists || actualValue != expectedValue {
+			if actualValue, exists := actualBody[key]; !exists || fmt.Sprint(actualValue) != expectedValue {
 				t.Errorf("Expected %s to be %s, got %s", key, expectedValue, actualValue)
 			}
 		}
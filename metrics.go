@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+)
+
+// outcomeOK is the outcome of a successful live lookup
+const outcomeOK = "ok"
+
+// lookupMetrics counts live lookups per store and outcome, where the outcome
+// is "ok" or the error code, so blocked scrapes are told from missing apps.
+type lookupMetrics struct {
+	counts map[string]map[string]int64
+	mu     sync.Mutex
+}
+
+// newLookupMetrics creates empty lookup counters.
+func newLookupMetrics() *lookupMetrics {
+	return &lookupMetrics{counts: make(map[string]map[string]int64)}
+}
+
+// record counts the outcome of a live lookup.
+func (m *lookupMetrics) record(storeName string, err error) {
+	outcome := outcomeOK
+	if err != nil {
+		_, outcome = classifyError(err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts[storeName] == nil {
+		m.counts[storeName] = make(map[string]int64)
+	}
+	m.counts[storeName][outcome]++
+}
+
+// snapshot copies the counters.
+func (m *lookupMetrics) snapshot() map[string]map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[string]map[string]int64, len(m.counts))
+	for storeName, outcomes := range m.counts {
+		counts[storeName] = make(map[string]int64, len(outcomes))
+		for outcome, n := range outcomes {
+			counts[storeName][outcome] = n
+		}
+	}
+	return counts
+}
+
+// handleMetrics reports the live lookup counters per store and outcome.
+func (s *server) handleMetrics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{"lookups": s.metrics.snapshot()})
+	}
+}
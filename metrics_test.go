@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", Version: "1.0"}}
+	srv := newTestServer(t, fake)
+	mux := srv.routes()
+
+	lookup := func() {
+		req := httptest.NewRequest(http.MethodGet, "/fake?appId=1", http.NoBody)
+		req.Header.Set("Cache-Control", "no-cache")
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	lookup()
+	fake.err = fmt.Errorf("%w: captcha page", store.ErrBlocked)
+	lookup()
+	lookup()
+	fake.err = store.ErrAppNotFound
+	lookup()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Lookups map[string]map[string]int64 `json:"lookups"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, map[string]int64{
+		outcomeOK:           1,
+		codeUpstreamBlocked: 2,
+		codeAppNotFound:     1,
+	}, body.Lookups["fake"])
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/arisecode/katsini/storage"
+)
+
+// productRequest is the body of PUT /products/{name}.
+type productRequest struct {
+	// Apps maps store names to the app ID or bundle ID of the product
+	Apps map[string]string `json:"apps"`
+}
+
+// productApp is the state of a product in one store.
+type productApp struct {
+	Store   string `json:"store"`
+	ID      string `json:"id"`
+	Version string `json:"version,omitempty"`
+	Updated string `json:"updated,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Lagging bool   `json:"lagging"`
+}
+
+// productReport compares the versions of a product across its stores.
+type productReport struct {
+	Name          string       `json:"name"`
+	NewestVersion string       `json:"newestVersion"`
+	Apps          []productApp `json:"apps"`
+	Lagging       []string     `json:"lagging"`
+	// Consistent is true when every store answered with the same version
+	Consistent bool `json:"consistent"`
+}
+
+// handleProducts lists the registered products.
+func (s *server) handleProducts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		products, err := s.db.Products(r.Context())
+		if err != nil {
+			log.Printf("Failed to load products: %v", err)
+			writeError(w, r, http.StatusInternalServerError, "Failed to load products")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, products)
+	}
+}
+
+// handleProduct reports the release consistency of a product, and creates,
+// replaces and deletes products.
+func (s *server) handleProduct() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.reportProduct(w, r)
+		case http.MethodPut:
+			s.putProduct(w, r)
+		case http.MethodDelete:
+			s.deleteProduct(w, r)
+		default:
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	}
+}
+
+// putProduct creates the product named in the path or replaces its apps.
+func (s *server) putProduct(w http.ResponseWriter, r *http.Request) {
+	var req productRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(req.Apps) == 0 {
+		writeError(w, r, http.StatusBadRequest, "Please provide the apps of the product")
+		return
+	}
+	for storeName, id := range req.Apps {
+		if _, ok := s.registry.Get(storeName); !ok {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("Unknown store %q", storeName))
+			return
+		}
+		if id == "" {
+			writeError(w, r, http.StatusBadRequest, "Please provide an app id for "+storeName)
+			return
+		}
+	}
+
+	product := storage.Product{Name: r.PathValue("name"), Apps: req.Apps}
+	if err := s.db.PutProduct(r.Context(), product); err != nil {
+		log.Printf("Failed to store product: %v", err)
+		writeError(w, r, http.StatusInternalServerError, "Failed to store product")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, product)
+}
+
+// deleteProduct removes the product named in the path.
+func (s *server) deleteProduct(w http.ResponseWriter, r *http.Request) {
+	err := s.db.DeleteProduct(r.Context(), r.PathValue("name"))
+	if errors.Is(err, storage.ErrProductNotFound) {
+		writeError(w, r, http.StatusNotFound, "Product not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete product: %v", err)
+		writeError(w, r, http.StatusInternalServerError, "Failed to delete product")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reportProduct looks up the product named in the path in all its stores at
+// once, through the cache, and compares their versions.
+func (s *server) reportProduct(w http.ResponseWriter, r *http.Request) {
+	product, err := s.db.Product(r.Context(), r.PathValue("name"))
+	if errors.Is(err, storage.ErrProductNotFound) {
+		writeError(w, r, http.StatusNotFound, "Product not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load product: %v", err)
+		writeError(w, r, http.StatusInternalServerError, "Failed to load product")
+		return
+	}
+
+	query := r.URL.Query()
+	timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	refresh := strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache")
+	lang, country := query.Get("lang"), query.Get("country")
+
+	apps := make([]productApp, 0, len(product.Apps))
+	for storeName, id := range product.Apps {
+		apps = append(apps, productApp{Store: storeName, ID: id})
+	}
+	slices.SortFunc(apps, func(a, b productApp) int { return strings.Compare(a.Store, b.Store) })
+
+	var wg sync.WaitGroup
+	for i := range apps {
+		wg.Go(func() {
+			app := &apps[i]
+			st, ok := s.registry.Get(app.Store)
+			if !ok {
+				app.Error = fmt.Sprintf("unknown store %q", app.Store)
+				return
+			}
+			entry, _, err := s.lookup(ctx, st, storeQuery(st, app.ID, lang, country), refresh)
+			if err != nil {
+				app.Error = err.Error()
+				return
+			}
+			app.Version = entry.App.Version
+			app.Updated = entry.App.Updated
+		})
+	}
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, compareProductApps(product.Name, apps))
+}
+
+// compareProductApps finds the newest version of a product and the stores
+// lagging behind it. Stores whose lookup failed or whose version does not
+// parse are never lagging, but make the product inconsistent.
+func compareProductApps(name string, apps []productApp) productReport {
+	report := productReport{Name: name, Apps: apps, Lagging: []string{}, Consistent: true}
+
+	var newest version
+	for i := range apps {
+		if v, ok := parseVersion(apps[i].Version); ok && (newest == nil || v.compare(newest) > 0) {
+			newest = v
+			report.NewestVersion = apps[i].Version
+		}
+	}
+
+	for i := range apps {
+		app := &apps[i]
+		if app.Error != "" || !sameVersion(app.Version, apps[0].Version) {
+			report.Consistent = false
+		}
+		if v, ok := parseVersion(app.Version); ok && v.compare(newest) < 0 {
+			app.Lagging = true
+			report.Lagging = append(report.Lagging, app.Store)
+		}
+	}
+	return report
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+func TestProductHandler(t *testing.T) {
+	play := &fakeStore{app: store.App{AppID: "1", Version: "1.13", Updated: "02-03-2025"}}
+	apple := &fakeSearchStore{fakeStore: fakeStore{app: store.App{AppID: "2", Version: "1.13.0", Updated: "01-03-2025"}}}
+	huawei := &fakeReviewStore{fakeStore: fakeStore{app: store.App{AppID: "3", Version: "1.12", Updated: "01-02-2025"}}}
+	srv := newTestServer(t, play, apple, huawei)
+	mux := srv.routes()
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Cache-Control", "no-cache")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+	report := func() productReport {
+		rr := do(http.MethodGet, "/products/katsini", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var report productReport
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+		return report
+	}
+
+	rr := do(http.MethodPut, "/products/katsini", `{"apps":{"fake":"1","searchable":"2","reviewed":"3"}}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	got := report()
+	assert.Equal(t, productReport{
+		Name:          "katsini",
+		NewestVersion: "1.13",
+		Apps: []productApp{
+			{Store: "fake", ID: "1", Version: "1.13", Updated: "02-03-2025"},
+			{Store: "reviewed", ID: "3", Version: "1.12", Updated: "01-02-2025", Lagging: true},
+			{Store: "searchable", ID: "2", Version: "1.13.0", Updated: "01-03-2025"},
+		},
+		Lagging: []string{"reviewed"},
+	}, got)
+	assert.Equal(t, 1, huawei.calls)
+
+	huawei.app.Version = "1.13"
+	got = report()
+	assert.True(t, got.Consistent)
+	assert.Empty(t, got.Lagging)
+
+	// A store that fails cannot be shown to be consistent
+	play.err = store.ErrBlocked
+	got = report()
+	assert.False(t, got.Consistent)
+	assert.Empty(t, got.Lagging)
+	assert.NotEmpty(t, got.Apps[0].Error)
+
+	rr = do(http.MethodGet, "/products", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var products []storage.Product
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&products))
+	require.Len(t, products, 1)
+	assert.Equal(t, map[string]string{"fake": "1", "searchable": "2", "reviewed": "3"}, products[0].Apps)
+
+	rr = do(http.MethodDelete, "/products/katsini", "")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = do(http.MethodGet, "/products/katsini", "")
+	checkResponse(t, rr, http.StatusNotFound, map[string]string{"code": codeNotFound, "message": "Product not found"})
+	rr = do(http.MethodDelete, "/products/katsini", "")
+	checkResponse(t, rr, http.StatusNotFound, map[string]string{"code": codeNotFound, "message": "Product not found"})
+}
+
+func TestProductHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Invalid body",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid request body"},
+		},
+		{
+			name:           "No apps",
+			body:           `{"apps":{}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Please provide the apps of the product"},
+		},
+		{
+			name:           "Unknown store",
+			body:           `{"apps":{"unknown":"1"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": `Unknown store "unknown"`},
+		},
+		{
+			name:           "Missing id",
+			body:           `{"apps":{"fake":""}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Please provide an app id for fake"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/products/katsini", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
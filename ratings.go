@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log"
+	"net/http"
+)
+
+// handleRatingHistory returns the daily ratings katsini has recorded for an
+// app, each with the version that was live that day.
+func (s *server) handleRatingHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		storeName := query.Get("store")
+		id := query.Get("id")
+
+		if _, ok := s.registry.Get(storeName); !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+		if id == "" {
+			writeInvalidInput(w, r, storeName, "Please provide an app id")
+			return
+		}
+
+		days, err := s.db.RatingHistory(r.Context(), storeName, id)
+		if err != nil {
+			log.Printf("Failed to load rating history: %v", err)
+			writeError(w, r, http.StatusInternalServerError, "Failed to load rating history")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"store": storeName,
+			"id":    id,
+			"days":  days,
+		})
+	}
+}
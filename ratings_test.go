@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+func TestRatingHistoryHandler(t *testing.T) {
+	fake := &fakeStore{app: store.App{
+		AppID:       "1",
+		BundleID:    "com.example",
+		Title:       "Example",
+		Version:     "1.0",
+		Rating:      4.5,
+		RatingCount: 10,
+		Histogram:   []int64{0, 1, 0, 2, 7},
+	}}
+	srv := newTestServer(t, fake)
+	mux := srv.routes()
+
+	lookup := func() {
+		req := httptest.NewRequest(http.MethodGet, "/fake?appId=1", http.NoBody)
+		req.Header.Set("Cache-Control", "no-cache")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	lookup()
+	fake.app.Version = "1.1"
+	fake.app.Rating = 4.6
+	fake.app.RatingCount = 12
+	fake.app.Histogram = []int64{0, 1, 0, 2, 9}
+	lookup()
+
+	req := httptest.NewRequest(http.MethodGet, "/ratings/history?store=fake&id=com.example", http.NoBody)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Days []storage.RatingDay `json:"days"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	require.Len(t, body.Days, 1, "fetches of the same day share a bucket")
+	assert.Equal(t, "1.1", body.Days[0].Version)
+	assert.InDelta(t, 4.6, body.Days[0].Rating, 0.001)
+	assert.Equal(t, int64(12), body.Days[0].RatingCount)
+	assert.Equal(t, []int64{0, 1, 0, 2, 9}, body.Days[0].Histogram)
+}
+
+func TestRatingHistoryHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&id=1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Missing id",
+			query:          "?store=fake",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Please provide an app id"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ratings/history"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+	"github.com/arisecode/katsini/webhook"
+)
+
+// checkReviewAlerts fetches the newest reviews of a watched app and sends a
+// webhook event for every review not seen before that matches the watch's
+// review alerts. Failures are logged and retried at the next check.
+func (s *server) checkReviewAlerts(ctx context.Context, st store.Store, w storage.Watch, app store.App) {
+	lister, ok := st.(store.ReviewLister)
+	if !ok || w.ReviewAlerts == nil {
+		return
+	}
+
+	page, err := lister.Reviews(ctx, store.ReviewQuery{
+		ID:      cmp.Or(app.AppID, app.BundleID),
+		Lang:    w.Lang,
+		Country: w.Country,
+		Sort:    store.SortNewest,
+	})
+	if err != nil {
+		log.Printf("Failed to fetch reviews of watch %d on %s: %v", w.ID, w.Store, err)
+		return
+	}
+
+	ids := make([]string, 0, len(page.Reviews))
+	reviews := make(map[string]store.Review, len(page.Reviews))
+	for _, review := range page.Reviews {
+		ids = append(ids, review.ID)
+		reviews[review.ID] = review
+	}
+
+	detectedAt := time.Now()
+	unseen, err := s.db.MarkReviewsSeen(ctx, w.ID, ids, detectedAt)
+	if err != nil {
+		log.Printf("Failed to record reviews of watch %d: %v", w.ID, err)
+		return
+	}
+
+	for _, id := range unseen {
+		review := reviews[id]
+		reasons := reviewAlertReasons(*w.ReviewAlerts, review)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		log.Printf("Watch %d on %s has a new %d star review: %s", w.ID, w.Store, review.Rating, strings.Join(reasons, ", "))
+		s.webhooks.Notify(webhook.Event{
+			Type:       webhook.EventReviewAlert,
+			Store:      w.Store,
+			WatchID:    w.ID,
+			New:        app,
+			Review:     &review,
+			Reasons:    reasons,
+			DetectedAt: detectedAt,
+		})
+	}
+}
+
+// reviewAlertReasons returns why a review matches the alerts: "rating" when it
+// is rated at most MaxRating stars and "keyword:<keyword>" for every keyword
+// its title or body mentions.
+func reviewAlertReasons(alerts storage.ReviewAlerts, review store.Review) []string {
+	var reasons []string
+	if alerts.MaxRating > 0 && review.Rating <= alerts.MaxRating {
+		reasons = append(reasons, "rating")
+	}
+
+	text := strings.ToLower(review.Title + "\n" + review.Body)
+	for _, keyword := range alerts.Keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			reasons = append(reasons, "keyword:"+keyword)
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+	"github.com/arisecode/katsini/webhook"
+)
+
+func TestCheckReviewAlerts(t *testing.T) {
+	received := make(chan webhook.Event, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer receiver.Close()
+
+	fake := &fakeReviewStore{fakeStore: fakeStore{app: store.App{AppID: "42", Version: "1.0"}}}
+	srv := newTestServer(t, fake)
+	srv.webhooks = webhook.New(srv.db, webhook.Config{URLs: []string{receiver.URL}})
+
+	ctx := context.Background()
+	w, err := srv.db.CreateWatch(ctx, storage.Watch{
+		Store:        "reviewed",
+		AppID:        "42",
+		Country:      "de",
+		Interval:     storage.Duration(time.Hour),
+		ReviewAlerts: &storage.ReviewAlerts{Keywords: []string{"login"}, MaxRating: 2},
+	})
+	require.NoError(t, err)
+
+	// The first check records the existing reviews without alerting
+	fake.reviews = []store.Review{{ID: "r1", Rating: 1, Body: "Bad."}}
+	_, err = srv.checkWatch(ctx, w)
+	require.NoError(t, err)
+	assert.Equal(t, store.ReviewQuery{ID: "42", Country: "de", Sort: store.SortNewest}, fake.query)
+
+	fake.reviews = []store.Review{
+		{ID: "r4", Rating: 1, Title: "Crash", Body: "Crashes after LOGIN."},
+		{ID: "r3", Rating: 4, Body: "Nice, but the login is slow."},
+		{ID: "r2", Rating: 5, Body: "Great."},
+		{ID: "r1", Rating: 1, Body: "Bad."},
+	}
+	_, err = srv.checkWatch(ctx, w)
+	require.NoError(t, err)
+	_, err = srv.checkWatch(ctx, w)
+	require.NoError(t, err)
+	srv.webhooks.Close()
+	close(received)
+
+	var events []webhook.Event
+	for event := range received {
+		events = append(events, event)
+	}
+	require.Len(t, events, 2, "every matching review must alert once")
+
+	alerts := make(map[string][]string)
+	for _, event := range events {
+		assert.Equal(t, webhook.EventReviewAlert, event.Type)
+		assert.Equal(t, w.ID, event.WatchID)
+		assert.Equal(t, "1.0", event.New.Version)
+		require.NotNil(t, event.Review)
+		alerts[event.Review.ID] = event.Reasons
+	}
+	assert.Equal(t, map[string][]string{
+		"r4": {"rating", "keyword:login"},
+		"r3": {"keyword:login"},
+	}, alerts)
+}
+
+func TestReviewAlertReasons(t *testing.T) {
+	alerts := storage.ReviewAlerts{Keywords: []string{"crash", "Login"}, MaxRating: 2}
+	assert.Empty(t, reviewAlertReasons(alerts, store.Review{Rating: 3, Body: "Fine"}))
+	assert.Equal(t, []string{"rating"}, reviewAlertReasons(alerts, store.Review{Rating: 2}))
+	assert.Equal(t, []string{"keyword:crash", "keyword:Login"},
+		reviewAlertReasons(alerts, store.Review{Rating: 5, Title: "CRASH", Body: "at login"}))
+	assert.Empty(t, reviewAlertReasons(storage.ReviewAlerts{Keywords: []string{"crash"}}, store.Review{Rating: 1}))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// handleReviews returns a page of user reviews of an app. The page parameter
+// takes the nextPage token of the previous response.
+func (s *server) handleReviews() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		st, ok := s.registry.Get(query.Get("store"))
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+		lister, ok := st.(store.ReviewLister)
+		if !ok {
+			writeInvalidInput(w, r, st.Name(), st.Title()+" does not support reviews")
+			return
+		}
+
+		id := query.Get("id")
+		if id == "" {
+			writeInvalidInput(w, r, st.Name(), "Please provide an app id")
+			return
+		}
+
+		sort := cmp.Or(query.Get("sort"), store.SortNewest)
+		if !slices.Contains(store.ReviewSorts, sort) {
+			writeInvalidInput(w, r, st.Name(), "Please provide a valid sort: "+strings.Join(store.ReviewSorts, ", "))
+			return
+		}
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		page, err := lister.Reviews(ctx, store.ReviewQuery{
+			ID:      id,
+			Lang:    query.Get("lang"),
+			Country: query.Get("country"),
+			Sort:    sort,
+			Page:    query.Get("page"),
+		})
+		if err != nil {
+			writeLookupError(w, r, st.Name(), err)
+			return
+		}
+
+		response := map[string]any{
+			"store":   st.Name(),
+			"id":      id,
+			"sort":    sort,
+			"reviews": page.Reviews,
+		}
+		if page.NextPage != "" {
+			response["nextPage"] = page.NextPage
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// fakeReviewStore is a fakeStore that also serves reviews: the given reviews
+// when set, two pages of made up ones otherwise
+type fakeReviewStore struct {
+	fakeStore
+	query   store.ReviewQuery
+	reviews []store.Review
+}
+
+func (*fakeReviewStore) Name() string { return "reviewed" }
+
+func (f *fakeReviewStore) Reviews(_ context.Context, q store.ReviewQuery) (store.ReviewPage, error) {
+	f.query = q
+	if f.err != nil {
+		return store.ReviewPage{}, f.err
+	}
+	if f.reviews != nil {
+		return store.ReviewPage{Reviews: f.reviews}, nil
+	}
+	if q.Page == "" {
+		return store.ReviewPage{
+			Reviews: []store.Review{{
+				Date:   time.Date(2024, 5, 22, 16, 12, 33, 0, time.UTC),
+				Reply:  &store.ReviewReply{Date: time.Date(2024, 5, 23, 8, 0, 0, 0, time.UTC), Body: "Thanks!"},
+				ID:     "r1",
+				Author: "jdoe",
+				Body:   "Works well.",
+				Rating: 5,
+			}},
+			NextPage: "cursor",
+		}, nil
+	}
+	return store.ReviewPage{Reviews: []store.Review{{ID: "r2", Rating: 1}}}, nil
+}
+
+func TestReviewsHandler(t *testing.T) {
+	fake := &fakeReviewStore{}
+	srv := newTestServer(t, fake, &fakeStore{})
+	mux := srv.routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/reviews?store=reviewed&id=42&country=de&sort=helpful", http.NoBody)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Store    string         `json:"store"`
+		Sort     string         `json:"sort"`
+		NextPage string         `json:"nextPage"`
+		Reviews  []store.Review `json:"reviews"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, "reviewed", body.Store)
+	assert.Equal(t, store.SortHelpful, body.Sort)
+	assert.Equal(t, "cursor", body.NextPage)
+	require.Len(t, body.Reviews, 1)
+	assert.Equal(t, "Thanks!", body.Reviews[0].Reply.Body)
+	assert.Equal(t, store.ReviewQuery{ID: "42", Country: "de", Sort: store.SortHelpful}, fake.query)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reviews?store=reviewed&id=42&page=cursor", http.NoBody))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var last map[string]any
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&last))
+	assert.NotContains(t, last, "nextPage")
+	assert.Equal(t, store.ReviewQuery{ID: "42", Sort: store.SortNewest, Page: "cursor"}, fake.query)
+
+	fake.err = store.ErrAppNotFound
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reviews?store=reviewed&id=43", http.NoBody))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestReviewsHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&id=42",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Store without reviews",
+			query:          "?store=fake&id=42",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Fake Store does not support reviews"},
+		},
+		{
+			name:           "Missing id",
+			query:          "?store=reviewed",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide an app id"},
+		},
+		{
+			name:           "Invalid sort",
+			query:          "?store=reviewed&id=42&sort=rating",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid sort: newest, helpful"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeReviewStore{}, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/reviews"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
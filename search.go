@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/arisecode/katsini/store"
+)
+
+const (
+	// defaultSearchLimit is the number of search results returned without a limit parameter
+	defaultSearchLimit = 20
+	// maxSearchLimit caps the limit parameter of searches
+	maxSearchLimit = 100
+)
+
+// handleSearch finds apps by name in a single store.
+func (s *server) handleSearch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		st, ok := s.registry.Get(query.Get("store"))
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+		searcher, ok := st.(store.Searcher)
+		if !ok {
+			writeInvalidInput(w, r, st.Name(), st.Title()+" does not support search")
+			return
+		}
+
+		term := query.Get("term")
+		if term == "" {
+			writeInvalidInput(w, r, st.Name(), "Please provide a search term")
+			return
+		}
+
+		limit, err := parseLimit(query.Get("limit"), defaultSearchLimit, maxSearchLimit)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		results, err := searcher.Search(ctx, store.SearchQuery{
+			Term:    term,
+			Lang:    query.Get("lang"),
+			Country: query.Get("country"),
+			Limit:   limit,
+		})
+		if err != nil {
+			writeLookupError(w, r, st.Name(), err)
+			return
+		}
+		if len(results) > limit {
+			results = results[:limit]
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"store":   st.Name(),
+			"term":    term,
+			"results": results,
+		})
+	}
+}
+
+// parseLimit parses an optional positive limit query parameter, capping it at maxLimit.
+func parseLimit(value string, defaultLimit, maxLimit int) (int, error) {
+	if value == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid limit %q", value)
+	}
+	return min(limit, maxLimit), nil
+}
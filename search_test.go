@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// fakeSearchStore is a fakeStore that also supports search
+type fakeSearchStore struct {
+	fakeStore
+	query   store.SearchQuery
+	results []store.App
+}
+
+func (*fakeSearchStore) Name() string { return "searchable" }
+
+func (f *fakeSearchStore) Search(_ context.Context, q store.SearchQuery) ([]store.App, error) {
+	f.query = q
+	return f.results, f.err
+}
+
+func TestSearchHandler(t *testing.T) {
+	fake := &fakeSearchStore{results: []store.App{
+		{AppID: "1", Title: "Radio FM"},
+		{AppID: "2", Title: "Radio Garden"},
+		{AppID: "3", Title: "Radio Online"},
+	}}
+	srv := newTestServer(t, fake, &fakeStore{})
+	mux := srv.routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/search?store=searchable&term=radio&country=de&lang=de&limit=2", http.NoBody)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var body struct {
+		Store   string      `json:"store"`
+		Term    string      `json:"term"`
+		Results []store.App `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, "searchable", body.Store)
+	assert.Equal(t, "radio", body.Term)
+	assert.Equal(t, fake.results[:2], body.Results)
+	assert.Equal(t, store.SearchQuery{Term: "radio", Lang: "de", Country: "de", Limit: 2}, fake.query)
+
+	fake.err = store.ErrBlocked
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/search?store=searchable&term=radio", http.NoBody))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, defaultSearchLimit, fake.query.Limit)
+}
+
+func TestSearchHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&term=radio",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Store without search",
+			query:          "?store=fake&term=radio",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Fake Store does not support search"},
+		},
+		{
+			name:           "Missing term",
+			query:          "?store=searchable",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a search term"},
+		},
+		{
+			name:           "Invalid limit",
+			query:          "?store=searchable&term=radio&limit=-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": `invalid limit "-1"`},
+		},
+	}
+
+	srv := newTestServer(t, &fakeSearchStore{}, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/search"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := parseLimit("", 20, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 20, limit)
+
+	limit, err = parseLimit("500", 20, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 100, limit)
+
+	_, err = parseLimit("0", 20, 100)
+	assert.EqualError(t, err, `invalid limit "0"`)
+
+	_, err = parseLimit("ten", 20, 100)
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ChangelogEntry is one version of an app with the release notes recorded for it.
+type ChangelogEntry struct {
+	FirstSeen    time.Time `json:"firstSeen"`
+	Version      string    `json:"version"`
+	Updated      string    `json:"updated"`
+	ReleaseNotes string    `json:"releaseNotes"`
+}
+
+// Changelog returns the distinct versions of an app, identified by its app ID
+// or bundle ID, newest first. Versions seen without release notes have empty notes.
+func (d *DB) Changelog(ctx context.Context, storeName, id string) ([]ChangelogEntry, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		WITH app AS (
+			SELECT * FROM snapshots WHERE store = ?1 AND (app_id = ?2 OR bundle_id = ?2)
+		), latest AS (
+			SELECT version, updated,
+				ROW_NUMBER() OVER (PARTITION BY version ORDER BY last_seen DESC) AS rank
+			FROM app
+		)
+		SELECT a.version, MIN(a.first_seen), l.updated, COALESCE((
+			SELECT notes FROM release_notes r
+			WHERE r.store = ?1 AND (r.app_id = ?2 OR r.bundle_id = ?2) AND r.version = a.version
+			ORDER BY r.recorded_at DESC
+			LIMIT 1
+		), '')
+		FROM app a
+		JOIN latest l ON l.version = a.version AND l.rank = 1
+		GROUP BY a.version
+		ORDER BY MIN(a.first_seen) DESC, MIN(a.id) DESC`,
+		storeName, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changelog of %s %s: %w", storeName, id, err)
+	}
+	defer rows.Close()
+
+	changelog := []ChangelogEntry{}
+	for rows.Next() {
+		var e ChangelogEntry
+		var firstSeen int64
+		if err := rows.Scan(&e.Version, &firstSeen, &e.Updated, &e.ReleaseNotes); err != nil {
+			return nil, fmt.Errorf("failed to read changelog of %s %s: %w", storeName, id, err)
+		}
+		e.FirstSeen = fromMillis(firstSeen)
+		changelog = append(changelog, e)
+	}
+	return changelog, rows.Err()
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestChangelog(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	v1 := store.App{AppID: "1592213654", BundleID: "com.thinkdivergent", Title: "Think Divergent", Version: "2.0.12", Updated: "01-01-2025"}
+	v2 := v1
+	v2.Version = "2.0.13"
+	v2.Updated = "11-02-2025"
+	v2.ReleaseNotes = "Bug fixes."
+	edited := v2
+	edited.ReleaseNotes = "Bug fixes and a new dark mode."
+
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", v1, start))
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", v2, start.Add(time.Hour)))
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", edited, start.Add(2*time.Hour)))
+	// A late write of the older text does not replace the edited notes
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", v2, start.Add(90*time.Minute)))
+
+	changelog, err := db.Changelog(ctx, "appstore", "com.thinkdivergent")
+	require.NoError(t, err)
+	assert.Equal(t, []ChangelogEntry{
+		{Version: "2.0.13", Updated: "11-02-2025", ReleaseNotes: "Bug fixes and a new dark mode.", FirstSeen: start.Add(time.Hour)},
+		{Version: "2.0.12", Updated: "01-01-2025", FirstSeen: start},
+	}, changelog)
+
+	unknown, err := db.Changelog(ctx, "appstore", "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, unknown)
+	assert.NotNil(t, unknown)
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// ChartKey identifies a chart of a store.
+type ChartKey struct {
+	Store    string
+	Chart    string
+	Category string
+	Country  string
+}
+
+// ChartRank is the rank of an app in the snapshot of a day.
+type ChartRank struct {
+	// Day is the UTC date of the snapshot, as YYYY-MM-DD
+	Day  string `json:"day"`
+	Rank int    `json:"rank"`
+}
+
+// dayLayout is the layout of the UTC days of daily records
+const dayLayout = "2006-01-02"
+
+// RecordChart stores the ranking of a chart as the snapshot of the UTC day of
+// recordedAt, replacing an earlier snapshot of the same day.
+func (d *DB) RecordChart(ctx context.Context, key ChartKey, apps []store.App, recordedAt time.Time) error {
+	day := recordedAt.UTC().Format(dayLayout)
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to record %s chart of %s: %w", key.Chart, key.Store, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM chart_snapshots
+		WHERE store = ? AND chart = ? AND category = ? AND country = ? AND day = ?`,
+		key.Store, key.Chart, key.Category, key.Country, day,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record %s chart of %s: %w", key.Chart, key.Store, err)
+	}
+
+	for i := range apps {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO chart_snapshots (store, chart, category, country, day, rank, app_id, bundle_id, title, recorded_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			key.Store, key.Chart, key.Category, key.Country, day, i+1, apps[i].AppID, apps[i].BundleID, apps[i].Title, toMillis(recordedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record %s chart of %s: %w", key.Chart, key.Store, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record %s chart of %s: %w", key.Chart, key.Store, err)
+	}
+	return nil
+}
+
+// ChartHistory returns the daily ranks of an app, identified by its app ID or
+// bundle ID, in a chart, oldest first. Days the app was not ranked are omitted.
+func (d *DB) ChartHistory(ctx context.Context, key ChartKey, id string) ([]ChartRank, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT day, MIN(rank) FROM chart_snapshots
+		WHERE store = ?1 AND chart = ?2 AND category = ?3 AND country = ?4 AND (app_id = ?5 OR bundle_id = ?5)
+		GROUP BY day
+		ORDER BY day`,
+		key.Store, key.Chart, key.Category, key.Country, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s chart history of %s %s: %w", key.Chart, key.Store, id, err)
+	}
+	defer rows.Close()
+
+	ranks := []ChartRank{}
+	for rows.Next() {
+		var r ChartRank
+		if err := rows.Scan(&r.Day, &r.Rank); err != nil {
+			return nil, fmt.Errorf("failed to read %s chart history of %s %s: %w", key.Chart, key.Store, id, err)
+		}
+		ranks = append(ranks, r)
+	}
+	return ranks, rows.Err()
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestChartHistory(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	day1 := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	key := ChartKey{Store: "appstore", Chart: "top-free", Country: "us"}
+	radio := store.App{AppID: "1", BundleID: "com.radio", Title: "Radio"}
+	maps := store.App{AppID: "2", BundleID: "com.maps", Title: "Maps"}
+	notes := store.App{AppID: "3", BundleID: "com.notes", Title: "Notes"}
+
+	require.NoError(t, db.RecordChart(ctx, key, []store.App{maps, radio}, day1))
+	// A second snapshot on the same day replaces the first
+	require.NoError(t, db.RecordChart(ctx, key, []store.App{notes, maps, radio}, day1.Add(time.Hour)))
+	require.NoError(t, db.RecordChart(ctx, key, []store.App{radio, notes}, day2))
+	// Other charts do not leak into the history
+	require.NoError(t, db.RecordChart(ctx, ChartKey{Store: "appstore", Chart: "top-paid", Country: "us"}, []store.App{radio}, day1))
+
+	ranks, err := db.ChartHistory(ctx, key, "com.radio")
+	require.NoError(t, err)
+	assert.Equal(t, []ChartRank{{Day: "2025-03-01", Rank: 3}, {Day: "2025-03-02", Rank: 1}}, ranks)
+
+	ranks, err = db.ChartHistory(ctx, key, "2")
+	require.NoError(t, err)
+	assert.Equal(t, []ChartRank{{Day: "2025-03-01", Rank: 2}}, ranks)
+
+	ranks, err = db.ChartHistory(ctx, key, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, ranks)
+	assert.NotNil(t, ranks)
+}
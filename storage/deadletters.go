@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DeadLetter is a webhook delivery that failed after every retry.
+type DeadLetter struct {
+	FailedAt  time.Time       `json:"failedAt"`
+	URL       string          `json:"url"`
+	LastError string          `json:"lastError"`
+	Payload   json.RawMessage `json:"payload"`
+	ID        int64           `json:"id"`
+	Attempts  int             `json:"attempts"`
+}
+
+// RecordDeadLetter stores a failed webhook delivery.
+func (d *DB) RecordDeadLetter(ctx context.Context, dl DeadLetter) error {
+	_, err := d.db.ExecContext(ctx, `
+		INSERT INTO dead_letters (url, payload, attempts, last_error, failed_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		dl.URL, string(dl.Payload), dl.Attempts, dl.LastError, toMillis(dl.FailedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record dead letter for %s: %w", dl.URL, err)
+	}
+	return nil
+}
+
+// DeadLetters returns every failed webhook delivery, newest first.
+func (d *DB) DeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT id, url, payload, attempts, last_error, failed_at
+		FROM dead_letters
+		ORDER BY failed_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := []DeadLetter{}
+	for rows.Next() {
+		var dl DeadLetter
+		var payload string
+		var failedAt int64
+		if err := rows.Scan(&dl.ID, &dl.URL, &payload, &dl.Attempts, &dl.LastError, &failedAt); err != nil {
+			return nil, fmt.Errorf("failed to read dead letter: %w", err)
+		}
+		dl.Payload = json.RawMessage(payload)
+		dl.FailedAt = fromMillis(failedAt)
+		letters = append(letters, dl)
+	}
+	return letters, rows.Err()
+}
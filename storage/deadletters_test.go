@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetters(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	letters, err := db.DeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+
+	require.NoError(t, db.RecordDeadLetter(ctx, DeadLetter{
+		URL:       "http://example.com/hook",
+		Payload:   json.RawMessage(`{"store":"appstore"}`),
+		Attempts:  5,
+		LastError: "unexpected status 500",
+		FailedAt:  now,
+	}))
+	require.NoError(t, db.RecordDeadLetter(ctx, DeadLetter{
+		URL:       "http://example.com/other",
+		Payload:   json.RawMessage(`{}`),
+		Attempts:  5,
+		LastError: "connection refused",
+		FailedAt:  now.Add(time.Minute),
+	}))
+
+	letters, err = db.DeadLetters(ctx)
+	require.NoError(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, "http://example.com/other", letters[0].URL)
+	assert.Equal(t, "http://example.com/hook", letters[1].URL)
+	assert.JSONEq(t, `{"store":"appstore"}`, string(letters[1].Payload))
+	assert.Equal(t, 5, letters[1].Attempts)
+	assert.Equal(t, now, letters[1].FailedAt)
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrProductNotFound = errors.New("product not found")
+
+// Product is one product shipped to several stores.
+type Product struct {
+	// Apps maps store names to the app ID or bundle ID of the product in that store
+	Apps map[string]string `json:"apps"`
+	Name string            `json:"name"`
+}
+
+// PutProduct creates a product or replaces the apps of an existing one.
+func (d *DB) PutProduct(ctx context.Context, p Product) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to store product %s: %w", p.Name, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_apps WHERE product = ?`, p.Name); err != nil {
+		return fmt.Errorf("failed to store product %s: %w", p.Name, err)
+	}
+	for storeName, app := range p.Apps {
+		_, err := tx.ExecContext(ctx, `INSERT INTO product_apps (product, store, app) VALUES (?, ?, ?)`, p.Name, storeName, app)
+		if err != nil {
+			return fmt.Errorf("failed to store product %s: %w", p.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to store product %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// Products returns every product, by name.
+func (d *DB) Products(ctx context.Context) ([]Product, error) {
+	return d.queryProducts(ctx, `SELECT product, store, app FROM product_apps ORDER BY product`)
+}
+
+// Product returns the product with the given name.
+func (d *DB) Product(ctx context.Context, name string) (Product, error) {
+	products, err := d.queryProducts(ctx, `SELECT product, store, app FROM product_apps WHERE product = ?`, name)
+	if err != nil {
+		return Product{}, err
+	}
+	if len(products) == 0 {
+		return Product{}, ErrProductNotFound
+	}
+	return products[0], nil
+}
+
+// DeleteProduct removes a product.
+func (d *DB) DeleteProduct(ctx context.Context, name string) error {
+	res, err := d.db.ExecContext(ctx, `DELETE FROM product_apps WHERE product = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete product %s: %w", name, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// queryProducts runs a query selecting product, store and app, ordered by product.
+func (d *DB) queryProducts(ctx context.Context, query string, args ...any) ([]Product, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query products: %w", err)
+	}
+	defer rows.Close()
+
+	products := []Product{}
+	for rows.Next() {
+		var name, storeName, app string
+		if err := rows.Scan(&name, &storeName, &app); err != nil {
+			return nil, fmt.Errorf("failed to read product: %w", err)
+		}
+		if len(products) == 0 || products[len(products)-1].Name != name {
+			products = append(products, Product{Name: name, Apps: make(map[string]string)})
+		}
+		products[len(products)-1].Apps[storeName] = app
+	}
+	return products, rows.Err()
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProducts(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	require.NoError(t, db.PutProduct(ctx, Product{Name: "katsini", Apps: map[string]string{
+		"playstore": "com.arise.katsini",
+		"appstore":  "1592213654",
+	}}))
+	require.NoError(t, db.PutProduct(ctx, Product{Name: "beyondium", Apps: map[string]string{"playstore": "com.mediocre.dirac"}}))
+
+	// Putting a product again replaces its apps
+	require.NoError(t, db.PutProduct(ctx, Product{Name: "katsini", Apps: map[string]string{
+		"appstore":   "1592213654",
+		"appgallery": "C100000000",
+	}}))
+
+	product, err := db.Product(ctx, "katsini")
+	require.NoError(t, err)
+	assert.Equal(t, Product{Name: "katsini", Apps: map[string]string{"appstore": "1592213654", "appgallery": "C100000000"}}, product)
+
+	products, err := db.Products(ctx)
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "beyondium", products[0].Name)
+	assert.Equal(t, "katsini", products[1].Name)
+
+	require.NoError(t, db.DeleteProduct(ctx, "katsini"))
+	assert.ErrorIs(t, db.DeleteProduct(ctx, "katsini"), ErrProductNotFound)
+	_, err = db.Product(ctx, "katsini")
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
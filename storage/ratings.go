@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// RatingDay is the rating of an app on a day, as last fetched that day.
+type RatingDay struct {
+	// Day is the UTC date of the fetch, as YYYY-MM-DD
+	Day string `json:"day"`
+	// Version is the version that was live when the rating was fetched
+	Version string `json:"version"`
+	// Histogram counts the ratings of 1 to 5 stars, where the store exposes it
+	Histogram   []int64 `json:"histogram,omitempty"`
+	Rating      float64 `json:"rating"`
+	RatingCount int64   `json:"ratingCount"`
+}
+
+// recordRating stores the rating of a lookup as the rating of the UTC day of
+// fetchedAt, unless a later fetch of the same day was already recorded.
+func recordRating(ctx context.Context, tx *sql.Tx, storeName string, app store.App, fetchedAt time.Time) error {
+	var histogram sql.NullString
+	if len(app.Histogram) > 0 {
+		encoded, err := json.Marshal(app.Histogram)
+		if err != nil {
+			return fmt.Errorf("failed to encode rating histogram of %s %s: %w", storeName, appKey(app), err)
+		}
+		histogram = sql.NullString{String: string(encoded), Valid: true}
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO ratings (store, app_id, bundle_id, day, version, rating, rating_count, histogram, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (store, app_id, bundle_id, day) DO UPDATE SET
+			version      = excluded.version,
+			rating       = excluded.rating,
+			rating_count = excluded.rating_count,
+			histogram    = excluded.histogram,
+			recorded_at  = excluded.recorded_at
+		WHERE excluded.recorded_at >= recorded_at`,
+		storeName, app.AppID, app.BundleID, fetchedAt.UTC().Format(dayLayout), app.Version,
+		app.Rating, app.RatingCount, histogram, toMillis(fetchedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record rating of %s %s: %w", storeName, appKey(app), err)
+	}
+	return nil
+}
+
+// RatingHistory returns the daily ratings of an app, identified by its app ID
+// or bundle ID, oldest first. Days without a fetch are omitted.
+func (d *DB) RatingHistory(ctx context.Context, storeName, id string) ([]RatingDay, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT day, version, rating, rating_count, histogram FROM ratings
+		WHERE store = ?1 AND (app_id = ?2 OR bundle_id = ?2)
+		ORDER BY day`,
+		storeName, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rating history of %s %s: %w", storeName, id, err)
+	}
+	defer rows.Close()
+
+	days := []RatingDay{}
+	for rows.Next() {
+		var day RatingDay
+		var histogram sql.NullString
+		if err := rows.Scan(&day.Day, &day.Version, &day.Rating, &day.RatingCount, &histogram); err != nil {
+			return nil, fmt.Errorf("failed to read rating history of %s %s: %w", storeName, id, err)
+		}
+		if histogram.Valid {
+			if err := json.Unmarshal([]byte(histogram.String), &day.Histogram); err != nil {
+				return nil, fmt.Errorf("failed to decode rating histogram of %s %s: %w", storeName, id, err)
+			}
+		}
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
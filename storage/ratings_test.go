@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestRatingHistory(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	day := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	app := store.App{BundleID: "com.mediocre.dirac", Version: "1.1.4", Rating: 4.1, RatingCount: 2000, Histogram: []int64{200, 100, 200, 500, 1000}}
+	require.NoError(t, db.RecordSnapshot(ctx, "playstore", app, day))
+
+	// The last fetch of a day wins, even when written out of order
+	released := app
+	released.Version = "1.1.5"
+	released.Rating = 4.3
+	released.RatingCount = 2345
+	released.Histogram = []int64{210, 95, 240, 500, 1300}
+	require.NoError(t, db.RecordSnapshot(ctx, "playstore", released, day.Add(8*time.Hour)))
+	require.NoError(t, db.RecordSnapshot(ctx, "playstore", app, day.Add(4*time.Hour)))
+
+	// Stores without a histogram still record the average and count
+	next := released
+	next.Histogram = nil
+	next.RatingCount = 2400
+	require.NoError(t, db.RecordSnapshot(ctx, "playstore", next, day.Add(24*time.Hour)))
+
+	// Lookups without a rating record none
+	unrated := store.App{BundleID: "com.mediocre.dirac", Version: "1.1.5"}
+	require.NoError(t, db.RecordSnapshot(ctx, "playstore", unrated, day.Add(48*time.Hour)))
+
+	history, err := db.RatingHistory(ctx, "playstore", "com.mediocre.dirac")
+	require.NoError(t, err)
+	assert.Equal(t, []RatingDay{
+		{Day: "2025-03-01", Version: "1.1.5", Histogram: []int64{210, 95, 240, 500, 1300}, Rating: 4.3, RatingCount: 2345},
+		{Day: "2025-03-02", Version: "1.1.5", Rating: 4.3, RatingCount: 2400},
+	}, history)
+
+	unknown, err := db.RatingHistory(ctx, "appstore", "com.mediocre.dirac")
+	require.NoError(t, err)
+	assert.Empty(t, unknown)
+	assert.NotNil(t, unknown)
+}
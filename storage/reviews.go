@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ReviewAlerts configures which new reviews of a watched app raise an alert.
+type ReviewAlerts struct {
+	// Keywords alert on reviews mentioning any of them, ignoring case
+	Keywords []string `json:"keywords,omitempty"`
+	// MaxRating alerts on reviews of at most this many stars; 0 disables it
+	MaxRating int `json:"maxRating,omitempty"`
+}
+
+// MarkReviewsSeen records reviews of a watched app as seen and returns the IDs
+// of those not seen before, in the given order. The first call for a watch only
+// records a baseline and returns none, so enabling alerts on an app does not
+// alert on every review it already has.
+func (d *DB) MarkReviewsSeen(ctx context.Context, watchID int64, reviewIDs []string, seenAt time.Time) ([]string, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark reviews of watch %d seen: %w", watchID, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var baselineAt sql.NullInt64
+	err = tx.QueryRowContext(ctx, `SELECT baseline_at FROM review_alerts WHERE watch_id = ?`, watchID).Scan(&baselineAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review alerts of watch %d: %w", watchID, err)
+	}
+
+	unseen := []string{}
+	for _, id := range reviewIDs {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO seen_reviews (watch_id, review_id, seen_at) VALUES (?, ?, ?)
+			ON CONFLICT DO NOTHING`,
+			watchID, id, toMillis(seenAt),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark reviews of watch %d seen: %w", watchID, err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 && baselineAt.Valid {
+			unseen = append(unseen, id)
+		}
+	}
+
+	if !baselineAt.Valid {
+		_, err = tx.ExecContext(ctx, `UPDATE review_alerts SET baseline_at = ? WHERE watch_id = ?`, toMillis(seenAt), watchID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark reviews of watch %d seen: %w", watchID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to mark reviews of watch %d seen: %w", watchID, err)
+	}
+	return unseen, nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkReviewsSeen(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	w, err := db.CreateWatch(ctx, Watch{
+		Store:        "playstore",
+		BundleID:     "com.mediocre.dirac",
+		Interval:     Duration(time.Hour),
+		ReviewAlerts: &ReviewAlerts{Keywords: []string{"crash"}, MaxRating: 2},
+		CreatedAt:    now,
+		NextCheck:    now,
+	})
+	require.NoError(t, err)
+
+	got, err := db.Watch(ctx, w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &ReviewAlerts{Keywords: []string{"crash"}, MaxRating: 2}, got.ReviewAlerts)
+
+	unseen, err := db.MarkReviewsSeen(ctx, w.ID, []string{"r1", "r2"}, now)
+	require.NoError(t, err)
+	assert.Empty(t, unseen, "the first check must only record a baseline")
+
+	unseen, err = db.MarkReviewsSeen(ctx, w.ID, []string{"r4", "r3", "r2"}, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"r4", "r3"}, unseen)
+
+	unseen, err = db.MarkReviewsSeen(ctx, w.ID, []string{"r4", "r3"}, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, unseen)
+
+	// Deleting the watch deletes its review alerts
+	require.NoError(t, db.DeleteWatch(ctx, w.ID))
+	_, err = db.MarkReviewsSeen(ctx, w.ID, []string{"r5"}, now)
+	assert.Error(t, err)
+
+	plain, err := db.CreateWatch(ctx, Watch{Store: "appstore", AppID: "1", Interval: Duration(time.Hour)})
+	require.NoError(t, err)
+	got, err = db.Watch(ctx, plain.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.ReviewAlerts)
+}
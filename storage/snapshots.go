@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// VersionHistory is one version of an app and when katsini saw it live.
+type VersionHistory struct {
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Version   string    `json:"version"`
+	Updated   string    `json:"updated"`
+	Title     string    `json:"title"`
+	Developer string    `json:"developer"`
+}
+
+// contentHash identifies the content of an app, so repeated identical lookups share a snapshot.
+func contentHash(app store.App) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		app.Version,
+		app.Updated,
+		app.Title,
+		app.Developer,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordSnapshot stores a successful lookup fetched at fetchedAt. Identical
+// content only extends the last-seen time of the existing snapshot. Release
+// notes are kept per version, the latest text replacing earlier ones, and
+// ratings per day, the latest fetch of the day replacing earlier ones.
+func (d *DB) RecordSnapshot(ctx context.Context, storeName string, app store.App, fetchedAt time.Time) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to record snapshot of %s %s: %w", storeName, appKey(app), err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO snapshots (store, app_id, bundle_id, version, updated, title, developer, content_hash, first_seen, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (store, app_id, bundle_id, content_hash) DO UPDATE SET
+			first_seen  = MIN(first_seen, excluded.first_seen),
+			last_seen   = MAX(last_seen, excluded.last_seen),
+			fetch_count = fetch_count + 1`,
+		storeName, app.AppID, app.BundleID, app.Version, app.Updated, app.Title, app.Developer,
+		contentHash(app), toMillis(fetchedAt), toMillis(fetchedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record snapshot of %s %s: %w", storeName, appKey(app), err)
+	}
+
+	if app.ReleaseNotes != "" {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO release_notes (store, app_id, bundle_id, version, notes, recorded_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (store, app_id, bundle_id, version) DO UPDATE SET
+				notes       = excluded.notes,
+				recorded_at = excluded.recorded_at
+			WHERE excluded.recorded_at >= recorded_at`,
+			storeName, app.AppID, app.BundleID, app.Version, app.ReleaseNotes, toMillis(fetchedAt),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record release notes of %s %s: %w", storeName, appKey(app), err)
+		}
+	}
+
+	if app.Rating > 0 || app.RatingCount > 0 {
+		if err := recordRating(ctx, tx, storeName, app, fetchedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record snapshot of %s %s: %w", storeName, appKey(app), err)
+	}
+	return nil
+}
+
+// History returns the distinct versions of an app, identified by its app ID
+// or bundle ID, oldest first.
+func (d *DB) History(ctx context.Context, storeName, id string) ([]VersionHistory, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		WITH app AS (
+			SELECT * FROM snapshots WHERE store = ?1 AND (app_id = ?2 OR bundle_id = ?2)
+		), latest AS (
+			SELECT version, updated, title, developer,
+				ROW_NUMBER() OVER (PARTITION BY version ORDER BY last_seen DESC) AS rank
+			FROM app
+		)
+		SELECT a.version, MIN(a.first_seen), MAX(a.last_seen), l.updated, l.title, l.developer
+		FROM app a
+		JOIN latest l ON l.version = a.version AND l.rank = 1
+		GROUP BY a.version
+		ORDER BY MIN(a.first_seen)`,
+		storeName, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history of %s %s: %w", storeName, id, err)
+	}
+	defer rows.Close()
+
+	history := []VersionHistory{}
+	for rows.Next() {
+		var v VersionHistory
+		var firstSeen, lastSeen int64
+		if err := rows.Scan(&v.Version, &firstSeen, &lastSeen, &v.Updated, &v.Title, &v.Developer); err != nil {
+			return nil, fmt.Errorf("failed to read history of %s %s: %w", storeName, id, err)
+		}
+		v.FirstSeen = fromMillis(firstSeen)
+		v.LastSeen = fromMillis(lastSeen)
+		history = append(history, v)
+	}
+	return history, rows.Err()
+}
+
+// appKey names an app in error messages
+func appKey(app store.App) string {
+	if app.AppID != "" {
+		return app.AppID
+	}
+	return app.BundleID
+}
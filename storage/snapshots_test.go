@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// openTestDB opens an empty database in a temporary directory
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "katsini.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestHistory(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	v1 := store.App{AppID: "1592213654", BundleID: "com.thinkdivergent", Title: "Think Divergent", Version: "2.0.12", Updated: "01-01-2025"}
+	v2 := v1
+	v2.Version = "2.0.13"
+	v2.Updated = "11-02-2025"
+
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", v1, start))
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", v1, start.Add(time.Hour)))
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", v2, start.Add(2*time.Hour)))
+	require.NoError(t, db.RecordSnapshot(ctx, "appstore", v2, start.Add(3*time.Hour)))
+	// Other stores do not leak into the history
+	require.NoError(t, db.RecordSnapshot(ctx, "playstore", v1, start))
+
+	byAppID, err := db.History(ctx, "appstore", "1592213654")
+	require.NoError(t, err)
+	assert.Equal(t, []VersionHistory{
+		{Version: "2.0.12", Updated: "01-01-2025", Title: "Think Divergent", FirstSeen: start, LastSeen: start.Add(time.Hour)},
+		{Version: "2.0.13", Updated: "11-02-2025", Title: "Think Divergent", FirstSeen: start.Add(2 * time.Hour), LastSeen: start.Add(3 * time.Hour)},
+	}, byAppID)
+
+	byBundleID, err := db.History(ctx, "appstore", "com.thinkdivergent")
+	require.NoError(t, err)
+	assert.Equal(t, byAppID, byBundleID)
+
+	var rows, fetches int
+	require.NoError(t, db.db.QueryRowContext(ctx, `SELECT COUNT(*), SUM(fetch_count) FROM snapshots WHERE store = 'appstore'`).Scan(&rows, &fetches))
+	assert.Equal(t, 2, rows, "identical content must be deduplicated")
+	assert.Equal(t, 4, fetches)
+}
+
+func TestHistoryUnknownApp(t *testing.T) {
+	db := openTestDB(t)
+
+	history, err := db.History(context.Background(), "appstore", "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+	assert.NotNil(t, history)
+}
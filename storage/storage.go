@@ -0,0 +1,151 @@
+// Package storage persists katsini data in an embedded SQLite database.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	// Registers the pure Go "sqlite" driver, so the server still builds without cgo
+	_ "modernc.org/sqlite"
+)
+
+// schema creates every table used by katsini; statements must be idempotent.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS snapshots (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		store        TEXT    NOT NULL,
+		app_id       TEXT    NOT NULL,
+		bundle_id    TEXT    NOT NULL,
+		version      TEXT    NOT NULL,
+		updated      TEXT    NOT NULL,
+		title        TEXT    NOT NULL,
+		developer    TEXT    NOT NULL,
+		content_hash TEXT    NOT NULL,
+		first_seen   INTEGER NOT NULL,
+		last_seen    INTEGER NOT NULL,
+		fetch_count  INTEGER NOT NULL DEFAULT 1,
+		UNIQUE (store, app_id, bundle_id, content_hash)
+	)`,
+	`CREATE INDEX IF NOT EXISTS snapshots_app_id ON snapshots (store, app_id)`,
+	`CREATE INDEX IF NOT EXISTS snapshots_bundle_id ON snapshots (store, bundle_id)`,
+	`CREATE TABLE IF NOT EXISTS release_notes (
+		store       TEXT    NOT NULL,
+		app_id      TEXT    NOT NULL,
+		bundle_id   TEXT    NOT NULL,
+		version     TEXT    NOT NULL,
+		notes       TEXT    NOT NULL,
+		recorded_at INTEGER NOT NULL,
+		PRIMARY KEY (store, app_id, bundle_id, version)
+	)`,
+	`CREATE TABLE IF NOT EXISTS ratings (
+		store        TEXT    NOT NULL,
+		app_id       TEXT    NOT NULL,
+		bundle_id    TEXT    NOT NULL,
+		day          TEXT    NOT NULL,
+		version      TEXT    NOT NULL,
+		rating       REAL    NOT NULL,
+		rating_count INTEGER NOT NULL,
+		histogram    TEXT,
+		recorded_at  INTEGER NOT NULL,
+		PRIMARY KEY (store, app_id, bundle_id, day)
+	)`,
+	`CREATE TABLE IF NOT EXISTS chart_snapshots (
+		store       TEXT    NOT NULL,
+		chart       TEXT    NOT NULL,
+		category    TEXT    NOT NULL,
+		country     TEXT    NOT NULL,
+		day         TEXT    NOT NULL,
+		rank        INTEGER NOT NULL,
+		app_id      TEXT    NOT NULL,
+		bundle_id   TEXT    NOT NULL,
+		title       TEXT    NOT NULL,
+		recorded_at INTEGER NOT NULL,
+		PRIMARY KEY (store, chart, category, country, day, rank)
+	)`,
+	`CREATE TABLE IF NOT EXISTS watches (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		store        TEXT    NOT NULL,
+		app_id       TEXT    NOT NULL,
+		bundle_id    TEXT    NOT NULL,
+		lang         TEXT    NOT NULL,
+		country      TEXT    NOT NULL,
+		interval_ms  INTEGER NOT NULL,
+		created_at   INTEGER NOT NULL,
+		next_check   INTEGER NOT NULL,
+		last_checked INTEGER,
+		last_result  TEXT,
+		last_error   TEXT    NOT NULL DEFAULT '',
+		UNIQUE (store, app_id, bundle_id, lang, country)
+	)`,
+	`CREATE INDEX IF NOT EXISTS watches_next_check ON watches (next_check)`,
+	`CREATE TABLE IF NOT EXISTS review_alerts (
+		watch_id    INTEGER PRIMARY KEY,
+		max_rating  INTEGER NOT NULL,
+		keywords    TEXT    NOT NULL,
+		baseline_at INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS seen_reviews (
+		watch_id  INTEGER NOT NULL,
+		review_id TEXT    NOT NULL,
+		seen_at   INTEGER NOT NULL,
+		PRIMARY KEY (watch_id, review_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS product_apps (
+		product TEXT NOT NULL,
+		store   TEXT NOT NULL,
+		app     TEXT NOT NULL,
+		PRIMARY KEY (product, store)
+	)`,
+	`CREATE TABLE IF NOT EXISTS dead_letters (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		url        TEXT    NOT NULL,
+		payload    TEXT    NOT NULL,
+		attempts   INTEGER NOT NULL,
+		last_error TEXT    NOT NULL,
+		failed_at  INTEGER NOT NULL
+	)`,
+}
+
+// DB is the katsini database.
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database at path and applies the schema.
+// Use ":memory:" for a throwaway database.
+func Open(ctx context.Context, path string) (*DB, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	// SQLite allows a single writer; serializing connections avoids SQLITE_BUSY
+	// and keeps ":memory:" databases on one connection
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to migrate database %s: %w", path, err)
+		}
+	}
+
+	return &DB{db: db}, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// toMillis converts a time to the unix milliseconds stored in the database
+func toMillis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+// fromMillis converts unix milliseconds read from the database to a UTC time
+func fromMillis(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arisecode/katsini/store"
+)
+
+var (
+	ErrWatchExists   = errors.New("watch already exists")
+	ErrWatchNotFound = errors.New("watch not found")
+)
+
+// Duration is a time.Duration encoded in JSON as a string such as "1h30m".
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string such as "1h30m".
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"1h\": %w", err)
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Watch is an app katsini refreshes on an interval.
+type Watch struct {
+	CreatedAt   time.Time  `json:"createdAt"`
+	NextCheck   time.Time  `json:"nextCheck"`
+	LastChecked *time.Time `json:"lastChecked"`
+	LastResult  *store.App `json:"lastResult"`
+	// ReviewAlerts, if set, alerts on new reviews of the app at every check
+	ReviewAlerts *ReviewAlerts `json:"reviewAlerts,omitempty"`
+	Store        string        `json:"store"`
+	AppID        string        `json:"appId,omitempty"`
+	BundleID     string        `json:"bundleId,omitempty"`
+	Lang         string        `json:"lang,omitempty"`
+	Country      string        `json:"country,omitempty"`
+	LastError    string        `json:"lastError,omitempty"`
+	ID           int64         `json:"id"`
+	Interval     Duration      `json:"interval"`
+}
+
+// Query returns the lookup performed for the watch.
+func (w Watch) Query() store.Query {
+	return store.Query{
+		AppID:    w.AppID,
+		BundleID: w.BundleID,
+		Lang:     w.Lang,
+		Country:  w.Country,
+	}
+}
+
+// selectWatches selects every column of a watch, joining its review alerts.
+const selectWatches = `SELECT id, store, app_id, bundle_id, lang, country, interval_ms,
+	created_at, next_check, last_checked, last_result, last_error, max_rating, keywords
+	FROM watches LEFT JOIN review_alerts ON review_alerts.watch_id = watches.id`
+
+// CreateWatch stores a new watch, due at its NextCheck time.
+func (d *DB) CreateWatch(ctx context.Context, w Watch) (Watch, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Watch{}, fmt.Errorf("failed to create watch: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO watches (store, app_id, bundle_id, lang, country, interval_ms, created_at, next_check)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		w.Store, w.AppID, w.BundleID, w.Lang, w.Country,
+		time.Duration(w.Interval).Milliseconds(), toMillis(w.CreatedAt), toMillis(w.NextCheck),
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return Watch{}, ErrWatchExists
+		}
+		return Watch{}, fmt.Errorf("failed to create watch: %w", err)
+	}
+
+	w.ID, err = res.LastInsertId()
+	if err != nil {
+		return Watch{}, fmt.Errorf("failed to create watch: %w", err)
+	}
+
+	if w.ReviewAlerts != nil {
+		keywords, err := json.Marshal(w.ReviewAlerts.Keywords)
+		if err != nil {
+			return Watch{}, fmt.Errorf("failed to encode review alerts: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO review_alerts (watch_id, max_rating, keywords) VALUES (?, ?, ?)`,
+			w.ID, w.ReviewAlerts.MaxRating, string(keywords),
+		)
+		if err != nil {
+			return Watch{}, fmt.Errorf("failed to create watch: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Watch{}, fmt.Errorf("failed to create watch: %w", err)
+	}
+	return w, nil
+}
+
+// Watches returns every watch, oldest first.
+func (d *DB) Watches(ctx context.Context) ([]Watch, error) {
+	return d.queryWatches(ctx, selectWatches+` ORDER BY id`)
+}
+
+// DueWatches returns the watches whose next check is at or before now.
+func (d *DB) DueWatches(ctx context.Context, now time.Time) ([]Watch, error) {
+	return d.queryWatches(ctx, selectWatches+` WHERE next_check <= ? ORDER BY next_check`, toMillis(now))
+}
+
+// Watch returns the watch with the given ID.
+func (d *DB) Watch(ctx context.Context, id int64) (Watch, error) {
+	watches, err := d.queryWatches(ctx, selectWatches+` WHERE id = ?`, id)
+	if err != nil {
+		return Watch{}, err
+	}
+	if len(watches) == 0 {
+		return Watch{}, ErrWatchNotFound
+	}
+	return watches[0], nil
+}
+
+// DeleteWatch removes a watch with its review alerts and seen reviews.
+func (d *DB) DeleteWatch(ctx context.Context, id int64) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete watch %d: %w", id, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM watches WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete watch %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrWatchNotFound
+	}
+
+	for _, table := range []string{"review_alerts", "seen_reviews"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE watch_id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete watch %d: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete watch %d: %w", id, err)
+	}
+	return nil
+}
+
+// UpdateWatchResult records the outcome of a check. A failed check keeps the
+// last successful result and only records the error.
+func (d *DB) UpdateWatchResult(ctx context.Context, id int64, checkedAt, nextCheck time.Time, app *store.App, checkErr error) error {
+	var err error
+	if checkErr != nil {
+		_, err = d.db.ExecContext(ctx, `
+			UPDATE watches SET last_checked = ?, next_check = ?, last_error = ? WHERE id = ?`,
+			toMillis(checkedAt), toMillis(nextCheck), checkErr.Error(), id,
+		)
+	} else {
+		var result []byte
+		result, err = json.Marshal(app)
+		if err != nil {
+			return fmt.Errorf("failed to encode result of watch %d: %w", id, err)
+		}
+		_, err = d.db.ExecContext(ctx, `
+			UPDATE watches SET last_checked = ?, next_check = ?, last_result = ?, last_error = '' WHERE id = ?`,
+			toMillis(checkedAt), toMillis(nextCheck), string(result), id,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update watch %d: %w", id, err)
+	}
+	return nil
+}
+
+// queryWatches runs a query built on selectWatches.
+func (d *DB) queryWatches(ctx context.Context, query string, args ...any) ([]Watch, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watches: %w", err)
+	}
+	defer rows.Close()
+
+	watches := []Watch{}
+	for rows.Next() {
+		var w Watch
+		var interval, createdAt, nextCheck int64
+		var lastChecked, maxRating sql.NullInt64
+		var lastResult, keywords sql.NullString
+		if err := rows.Scan(&w.ID, &w.Store, &w.AppID, &w.BundleID, &w.Lang, &w.Country, &interval,
+			&createdAt, &nextCheck, &lastChecked, &lastResult, &w.LastError, &maxRating, &keywords); err != nil {
+			return nil, fmt.Errorf("failed to read watch: %w", err)
+		}
+
+		w.Interval = Duration(time.Duration(interval) * time.Millisecond)
+		w.CreatedAt = fromMillis(createdAt)
+		w.NextCheck = fromMillis(nextCheck)
+		if lastChecked.Valid {
+			t := fromMillis(lastChecked.Int64)
+			w.LastChecked = &t
+		}
+		if lastResult.Valid {
+			var app store.App
+			if err := json.Unmarshal([]byte(lastResult.String), &app); err != nil {
+				return nil, fmt.Errorf("failed to decode result of watch %d: %w", w.ID, err)
+			}
+			w.LastResult = &app
+		}
+		if keywords.Valid {
+			w.ReviewAlerts = &ReviewAlerts{MaxRating: int(maxRating.Int64)}
+			if err := json.Unmarshal([]byte(keywords.String), &w.ReviewAlerts.Keywords); err != nil {
+				return nil, fmt.Errorf("failed to decode review alerts of watch %d: %w", w.ID, err)
+			}
+		}
+		watches = append(watches, w)
+	}
+	return watches, rows.Err()
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+func TestWatches(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	w, err := db.CreateWatch(ctx, Watch{
+		Store:     "playstore",
+		BundleID:  "com.mediocre.dirac",
+		Country:   "us",
+		Interval:  Duration(time.Hour),
+		CreatedAt: now,
+		NextCheck: now,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, w.ID)
+
+	_, err = db.CreateWatch(ctx, Watch{Store: "playstore", BundleID: "com.mediocre.dirac", Country: "us", Interval: Duration(time.Minute)})
+	assert.ErrorIs(t, err, ErrWatchExists)
+
+	due, err := db.DueWatches(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, w.ID, due[0].ID)
+	assert.Equal(t, Duration(time.Hour), due[0].Interval)
+
+	app := store.App{BundleID: "com.mediocre.dirac", Version: "1.1.5"}
+	require.NoError(t, db.UpdateWatchResult(ctx, w.ID, now, now.Add(time.Hour), &app, nil))
+	require.NoError(t, db.UpdateWatchResult(ctx, w.ID, now.Add(time.Hour), now.Add(2*time.Hour), nil, errors.New("blocked")))
+
+	got, err := db.Watch(ctx, w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &app, got.LastResult, "a failed check must keep the last result")
+	assert.Equal(t, "blocked", got.LastError)
+	assert.Equal(t, now.Add(time.Hour), *got.LastChecked)
+
+	due, err = db.DueWatches(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	require.NoError(t, db.DeleteWatch(ctx, w.ID))
+	assert.ErrorIs(t, db.DeleteWatch(ctx, w.ID), ErrWatchNotFound)
+	_, err = db.Watch(ctx, w.ID)
+	assert.ErrorIs(t, err, ErrWatchNotFound)
+}
+
+func TestDurationJSON(t *testing.T) {
+	data, err := json.Marshal(Duration(90 * time.Minute))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"1h30m0s"`, string(data))
+
+	var d Duration
+	require.NoError(t, json.Unmarshal([]byte(`"15m"`), &d))
+	assert.Equal(t, Duration(15*time.Minute), d)
+
+	assert.Error(t, json.Unmarshal([]byte(`900`), &d))
+	assert.Error(t, json.Unmarshal([]byte(`"soon"`), &d))
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// App is the app information returned by every store.
+type App struct {
+	AppID     string `json:"appId,omitempty"` // Simple, unique identifier typically used within an app or system
+	BundleID  string `json:"bundleId"`        // Unique identifier for an entire app/application bundle
+	URL       string `json:"url"`             // URL of the app's page
+	Title     string `json:"title"`           // Title of the app
+	Version   string `json:"version"`         // Version of the app
+	Updated   string `json:"updated"`         // Last updated date of the app
+	Developer string `json:"developer"`       // Developer of the app
+
+	// Extended metadata, filled in where the store exposes it
+	DeveloperID   string  `json:"developerId,omitempty"`   // Store identifier of the developer, accepted by /developer
+	Installs      string  `json:"installs,omitempty"`      // Install bucket such as "10,000,000+"
+	Currency      string  `json:"currency,omitempty"`      // ISO 4217 code of the price's currency
+	Icon          string  `json:"icon,omitempty"`          // URL of the app icon
+	ContentRating string  `json:"contentRating,omitempty"` // Content or age rating, e.g. "Everyone" or "12+"
+	Category      string  `json:"category,omitempty"`      // Primary category or genre
+	ReleaseNotes  string  `json:"releaseNotes,omitempty"`  // What's new in the current version
+	MinOSVersion  string  `json:"minOsVersion,omitempty"`  // Minimum OS version required
+	Histogram     []int64 `json:"histogram,omitempty"`     // Number of ratings of 1 to 5 stars
+	Rating        float64 `json:"rating,omitempty"`        // Average user rating out of 5
+	RatingCount   int64   `json:"ratingCount,omitempty"`   // Number of user ratings
+	Price         float64 `json:"price,omitempty"`         // Price in Currency, omitted for free apps
+	Size          int64   `json:"size,omitempty"`          // Download size in bytes
+}
+
+const DefaultTimeout = 30 * time.Second
+
+// MaxRating is the highest star rating of apps and reviews.
+const MaxRating = 5
+
+// validateAppData checks that critical app fields are populated
+func validateAppData(app App, source string) error {
+	if app.Title == "" {
+		return fmt.Errorf("%s: missing app title", source)
+	}
+	if app.Version == "" {
+		return fmt.Errorf("%s: missing app version", source)
+	}
+	if app.BundleID == "" {
+		return fmt.Errorf("%s: missing bundle ID", source)
+	}
+	return nil
+}
@@ -0,0 +1,441 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+)
+
+// AppGallery exposes HuaweiAppGallery through the Store interface.
+type AppGallery struct{}
+
+func (AppGallery) Name() string { return "appgallery" }
+
+func (AppGallery) Title() string { return "Huawei AppGallery" }
+
+func (AppGallery) Identifiers() []Identifier { return []Identifier{IdentifierAppID} }
+
+func (AppGallery) Options() []string { return nil }
+
+func (AppGallery) Lookup(ctx context.Context, q Query) (App, error) {
+	return HuaweiAppGallery(ctx, q.AppID)
+}
+
+// HuaweiAppGallery scrapes an app from Huawei AppGallery, falling back to the
+// Publishing API when credentials are configured.
+func HuaweiAppGallery(ctx context.Context, appID string) (App, error) {
+	if err := validateID("appId", appID, numericID); err != nil {
+		return App{}, err
+	}
+
+	app, err := retryOperation(ctx, func(ctx context.Context) (App, error) {
+		return huaweiAppGalleryScrape(ctx, appID)
+	}, 3, fmt.Sprintf("HuaweiAppGallery scrape for appID %s", appID))
+
+	if err == nil {
+		return app, nil
+	}
+
+	// The API answers blocked clients, but a missing app is missing there too
+	if ctx.Err() == nil && !errors.Is(err, ErrAppNotFound) && shouldUseHuaweiAPIFallback() {
+		log.Printf("Falling back to Huawei AppGallery API for appID %s due to scrape error: %v", appID, err)
+		if fallback, apiErr := HuaweiAppGalleryByToken(ctx, appID); apiErr == nil {
+			return fallback, nil
+		} else {
+			log.Printf("Huawei AppGallery API fallback failed: %v", apiErr)
+		}
+	}
+
+	return App{}, err
+}
+
+func shouldUseHuaweiAPIFallback() bool {
+	return os.Getenv("HUAWEI_CLIENT_ID") != "" && os.Getenv("HUAWEI_CLIENT_SECRET") != ""
+}
+
+func huaweiAppGalleryScrape(ctx context.Context, appID string) (App, error) {
+	app := App{
+		AppID: appID,
+		URL:   fmt.Sprintf("https://appgallery.huawei.com/app/C%s", appID),
+	}
+
+	log.Printf("Fetching Huawei AppGallery app data for appID: %s", appID)
+
+	// Open an isolated incognito tab on a warm browser from the shared pool
+	taskCtx, release, err := DefaultBrowserPool().Acquire(ctx)
+	if err != nil {
+		return App{}, fmt.Errorf("failed to acquire browser tab: %w", err)
+	}
+
+	// Use shared resource blocking configuration
+	chromedp.ListenTarget(taskCtx, DisableFetchExceptScripts(taskCtx, commonResourceTypesToBlock))
+
+	timeoutCtx, cancel := context.WithTimeout(taskCtx, DefaultTimeout)
+	defer cancel()
+
+	var notFound bool
+	var page pageInfo
+	var content string
+
+	// Structure to hold all extracted data from JavaScript
+	var extractedData struct {
+		Title        string          `json:"title"`
+		Lang         string          `json:"lang"`
+		Developer    string          `json:"developer"`
+		DeveloperURL string          `json:"developerURL"`
+		BundleID     string          `json:"bundleID"`
+		Icon         string          `json:"icon"`
+		Rating       string          `json:"rating"`
+		Installs     string          `json:"installs"`
+		Notes        string          `json:"notes"`
+		Rows         []appGalleryRow `json:"rows"`
+	}
+
+	err = chromedp.Run(timeoutCtx,
+		fetch.Enable(),
+		chromedp.Navigate(app.URL),
+		// Verification and access denied pages never render the app card
+		chromedp.Evaluate(pageInfoScript, &page),
+		chromedp.ActionFunc(func(_ context.Context) error {
+			return classifyPage(page, appGalleryPageRules)
+		}),
+		chromedp.WaitVisible(`div[class="horizonhomecard"]`),
+		chromedp.WaitVisible(`div[class="componentContainer"]`),
+		// Check if app exists by examining component container height
+		// A height < 500px typically indicates an error or missing app page
+		chromedp.Evaluate(`document.querySelector('.componentContainer').offsetHeight < 500`, &notFound),
+		chromedp.Evaluate(`document.querySelector('.componentContainer').innerText.trim()`, &content),
+		chromedp.ActionFunc(func(_ context.Context) error {
+			if notFound {
+				return appGalleryMissingError(content, app.URL)
+			}
+			return nil
+		}),
+
+		chromedp.Evaluate(`
+			(function() {
+				const getTextByXPath = (xpath) => {
+					const result = document.evaluate(xpath, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null);
+					return result.singleNodeValue?.innerText?.trim() || '';
+				};
+
+				// Every label with the value next to it, read by label or shape in Go
+				const rows = [];
+				document.querySelectorAll('.componentContainer div').forEach((label) => {
+					const value = label.nextElementSibling;
+					if (label.children.length === 0 && value?.tagName === 'DIV') {
+						rows.push({label: label.innerText.trim(), value: value.innerText.trim()});
+					}
+				});
+				const developerLink = document.querySelector('.componentContainer a[href*="eveloper"]');
+
+				return {
+					title: document.querySelector('div.center_info > div.title')?.innerText?.trim() || '',
+					lang: document.documentElement.lang || '',
+					rows: rows,
+					developer: developerLink?.innerText?.trim() ||
+						getTextByXPath('//div[contains(text(), "Developer")]/following-sibling::div[1]'),
+					developerURL: developerLink?.href || '',
+					bundleID: document.querySelector('div[package]')?.getAttribute('package') || '',
+					icon: document.querySelector('div.left_logo img, div.horizonhomecard img')?.src || '',
+					rating: document.querySelector('div.center_info .score, div.horizonhomecard .score')?.innerText?.trim() || '',
+					installs: getTextByXPath('//div[@class="center_info"]//*[contains(text(), "installs")]'),
+					notes: getTextByXPath('//div[contains(text(), "What\'s new") or contains(text(), "Update description")]/following-sibling::div[1]')
+				};
+			})()
+		`, &extractedData),
+	)
+	release(err)
+	if err != nil {
+		return App{}, scrapeError(ctx, err, app.URL)
+	}
+
+	version, updated, size := appGalleryDetails(extractedData.Rows, extractedData.Lang)
+
+	app.Title = extractedData.Title
+	app.Version = version
+	app.Developer = extractedData.Developer
+	app.DeveloperID = appGalleryDeveloperID(extractedData.DeveloperURL)
+	app.BundleID = extractedData.BundleID
+	app.Icon = extractedData.Icon
+	app.ReleaseNotes = extractedData.Notes
+	app.Installs = strings.TrimSpace(strings.TrimSuffix(extractedData.Installs, "installs"))
+	app.Size = parseByteSize(size)
+	if rating, err := strconv.ParseFloat(extractedData.Rating, 64); err == nil {
+		app.Rating = rating
+	}
+
+	if err := validateAppData(app, "Huawei AppGallery scrape"); err != nil {
+		return App{}, err
+	}
+
+	parsedDate, err := parseFlexibleDate(updated, extractedData.Lang)
+	if err != nil {
+		return App{}, fmt.Errorf("%w: failed to parse update date %q: %w", ErrUpstream, updated, err)
+	}
+
+	app.Updated = parsedDate.Format("02-01-2006")
+
+	return app, nil
+}
+
+// appGalleryRow is a label and the value shown next to it on an AppGallery app page.
+type appGalleryRow struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+var (
+	// versionPattern matches version numbers such as "6.5.6" or "13.1.0.300";
+	// shorter ones cannot be told from the rating
+	versionPattern = regexp.MustCompile(`^\d+(\.\d+){2,}$`)
+	// dottedDate matches day first dates such as "31.10.2019", which look like versions
+	dottedDate = regexp.MustCompile(`^\d{1,2}\.\d{1,2}\.\d{4}$`)
+	// yearPattern matches the four digit year every AppGallery date carries
+	yearPattern = regexp.MustCompile(`[0-9٠-٩۰-۹]{4}`)
+)
+
+// appGalleryDetails picks the version, update date and size from the labelled
+// rows of an AppGallery app page. Rows with the English labels are read by
+// label; on pages in other languages the values are recognised by their shape,
+// dates in the page language lang.
+func appGalleryDetails(rows []appGalleryRow, lang string) (version, updated, size string) {
+	for _, row := range rows {
+		switch {
+		case version == "" && strings.Contains(row.Label, "Version"):
+			version = row.Value
+		case updated == "" && strings.Contains(row.Label, "Updated"):
+			updated = row.Value
+		case size == "" && strings.Contains(row.Label, "Size"):
+			size = row.Value
+		}
+	}
+
+	for _, row := range rows {
+		value := row.Value
+		if value == "" || value == version || value == updated || value == size {
+			continue
+		}
+		switch {
+		case version == "" && versionPattern.MatchString(value) && !dottedDate.MatchString(value):
+			version = value
+		case updated == "" && isDatedValue(value, lang):
+			updated = value
+		case size == "" && parseByteSize(value) > 0:
+			size = value
+		}
+	}
+	return version, updated, size
+}
+
+// isDatedValue reports whether value is a date with a year, written in the language of lang.
+func isDatedValue(value, lang string) bool {
+	if !yearPattern.MatchString(value) {
+		return false
+	}
+	_, err := parseFlexibleDate(value, lang)
+	return err == nil
+}
+
+// appGalleryDeveloperID returns the developer id of a developer page link such
+// as "https://appgallery.huawei.com/developer/890086000102017654", or of the
+// same route behind a fragment as in "https://appgallery.huawei.com/#/Developer/890086000102017654".
+func appGalleryDeveloperID(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	fragment, _, _ := strings.Cut(u.Fragment, "?")
+	segments := strings.Split(u.Path+"/"+fragment, "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if strings.EqualFold(segments[i], "developer") && segments[i+1] != "" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// appGalleryMissingError tells a missing app, whose page explains that the app
+// is unavailable, from the empty page AppGallery serves to blocked clients such
+// as datacenter IPs.
+func appGalleryMissingError(content, url string) error {
+	if content == "" {
+		return fmt.Errorf("%w: empty page at %s", ErrBlocked, url)
+	}
+	return ErrAppNotFound
+}
+
+// parseByteSize parses a size such as "45.32 MB" into bytes, returning 0 when
+// it is not a size.
+func parseByteSize(s string) int64 {
+	number, unit, _ := strings.Cut(strings.TrimSpace(s), " ")
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+
+	multipliers := map[string]float64{"B": 1, "KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30}
+	multiplier, ok := multipliers[strings.ToUpper(strings.TrimSpace(unit))]
+	if !ok {
+		return 0
+	}
+	return int64(value * multiplier)
+}
+
+// HuaweiAppGalleryByToken fetches an app from the AppGallery Publishing API.
+func HuaweiAppGalleryByToken(ctx context.Context, appID string) (App, error) {
+	if err := validateID("appId", appID, numericID); err != nil {
+		return App{}, err
+	}
+
+	token, err := getHuaweiToken(ctx)
+	if err != nil {
+		return App{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("https://connect-api.cloud.huawei.com/api/publish/v2/app-info?appId=%s", appID), http.NoBody)
+	if err != nil {
+		return App{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("client_id", os.Getenv("HUAWEI_CLIENT_ID"))
+
+	client := http.DefaultClient
+	resp, err := client.Do(req)
+	if err != nil {
+		return App{}, fmt.Errorf("%w: failed to get app from huawei api: %w", ErrPageLoad, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return App{}, statusError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return App{}, fmt.Errorf("%w: failed to read huawei api response: %w", ErrPageLoad, err)
+	}
+
+	var appResponse struct {
+		Ret struct {
+			Code string `json:"code"`
+			Msg  string `json:"msg"`
+		} `json:"ret"`
+		AppInfo struct {
+			AppName       string `json:"appName"`
+			PackageName   string `json:"packageName"`
+			VersionNumber string `json:"versionNumber"`
+			UpdateTime    string `json:"updateTime"`
+			DeveloperName string `json:"developerName"`
+		} `json:"appInfo"`
+		Languages []struct {
+			AppName  string `json:"appName"`
+			Language string `json:"language"`
+		} `json:"languages"`
+	}
+
+	if err := json.Unmarshal(body, &appResponse); err != nil {
+		return App{}, fmt.Errorf("%w: failed to decode huawei api response: %w", ErrUpstream, err)
+	}
+
+	if appResponse.Ret.Code != "" && appResponse.Ret.Code != "0" {
+		return App{}, fmt.Errorf("%w: huawei api returned error code %s: %s", ErrUpstream, appResponse.Ret.Code, appResponse.Ret.Msg)
+	}
+
+	parseDate, err := time.Parse("2006-01-02 15:04:05", appResponse.AppInfo.UpdateTime)
+	if err != nil {
+		return App{}, fmt.Errorf("%w: invalid update time %q: %w", ErrUpstream, appResponse.AppInfo.UpdateTime, err)
+	}
+
+	title := appResponse.AppInfo.AppName
+	if title == "" && len(appResponse.Languages) > 0 {
+		title = appResponse.Languages[0].AppName
+	}
+
+	bundleID := appResponse.AppInfo.PackageName
+	if bundleID == "" {
+		bundleID = appID
+	}
+
+	return App{
+		AppID:     appID,
+		BundleID:  bundleID,
+		URL:       fmt.Sprintf("https://appgallery.huawei.com/app/C%s", appID),
+		Title:     title,
+		Version:   appResponse.AppInfo.VersionNumber,
+		Updated:   parseDate.Format("02-01-2006"),
+		Developer: appResponse.AppInfo.DeveloperName,
+	}, nil
+}
+
+func getHuaweiToken(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	payload := map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     os.Getenv("HUAWEI_CLIENT_ID"),
+		"client_secret": os.Getenv("HUAWEI_CLIENT_SECRET"),
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://connect-api-dre.cloud.huawei.com/api/oauth2/v1/token", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := http.DefaultClient
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to get huawei token: %w", ErrPageLoad, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: huawei token request returned status %d", ErrUpstream, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read huawei token response: %w", ErrPageLoad, err)
+	}
+
+	// Parse the response using a struct
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", fmt.Errorf("%w: failed to decode huawei token response: %w", ErrUpstream, err)
+	}
+
+	return tokenResponse.AccessToken, nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	assert.Equal(t, int64(47521464), parseByteSize("45.32 MB"))
+	assert.Equal(t, int64(1536), parseByteSize("1.5 KB"))
+	assert.Equal(t, int64(2147483648), parseByteSize("2 gb"))
+	assert.Zero(t, parseByteSize(""))
+	assert.Zero(t, parseByteSize("45.32"))
+	assert.Zero(t, parseByteSize("large"))
+}
+
+func TestAppGalleryDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		version string
+		updated string
+		size    string
+		rows    []appGalleryRow
+	}{
+		{
+			name: "English labels", lang: "en", version: "6.5.6", updated: "10/31/2019", size: "45.32 MB",
+			rows: []appGalleryRow{
+				{Label: "Developer", Value: "RADIOFM"},
+				{Label: "Size", Value: "45.32 MB"},
+				{Label: "Version", Value: "6.5.6"},
+				{Label: "Updated", Value: "10/31/2019"},
+			},
+		},
+		{
+			name: "German", lang: "de-DE", version: "13.1.0.300", updated: "31.10.2019", size: "1024 MB",
+			rows: []appGalleryRow{
+				{Label: "Entwickler", Value: "Huawei Software Technologies Co., Ltd."},
+				{Label: "Größe", Value: "1024 MB"},
+				{Label: "Aktualisiert", Value: "31.10.2019"},
+				{Label: "Versionsnummer", Value: "13.1.0.300"},
+			},
+		},
+		{
+			name: "Japanese", lang: "ja", version: "2.4.1", updated: "2019/10/31", size: "12 MB",
+			rows: []appGalleryRow{
+				{Label: "評価", Value: "4.5"},
+				{Label: "バージョン", Value: "2.4.1"},
+				{Label: "更新日", Value: "2019/10/31"},
+				{Label: "サイズ", Value: "12 MB"},
+			},
+		},
+		{
+			name: "Arabic", lang: "ar", version: "1.0.2", updated: "٣١ أكتوبر ٢٠١٩", size: "8.5 MB",
+			rows: []appGalleryRow{
+				{Label: "الإصدار", Value: "1.0.2"},
+				{Label: "تاريخ التحديث", Value: "٣١ أكتوبر ٢٠١٩"},
+				{Label: "الحجم", Value: "8.5 MB"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, updated, size := appGalleryDetails(tt.rows, tt.lang)
+			assert.Equal(t, tt.version, version)
+			assert.Equal(t, tt.updated, updated)
+			assert.Equal(t, tt.size, size)
+		})
+	}
+}
+
+func TestAppGalleryDeveloperID(t *testing.T) {
+	assert.Equal(t, "890086000102017654", appGalleryDeveloperID("https://appgallery.huawei.com/developer/890086000102017654"))
+	assert.Equal(t, "890086000102017654", appGalleryDeveloperID("https://appgallery.huawei.com/#/Developer/890086000102017654?locale=en"))
+	assert.Empty(t, appGalleryDeveloperID("https://appgallery.huawei.com/app/C100102149"))
+	assert.Empty(t, appGalleryDeveloperID("https://appgallery.huawei.com/developer/"))
+	assert.Empty(t, appGalleryDeveloperID(""))
+}
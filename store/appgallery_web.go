@@ -0,0 +1,162 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// appGalleryWebAPI is the API behind the AppGallery website, replaced by tests.
+var appGalleryWebAPI = "https://web-dra.hispace.dbankcloud.com/uowap/index"
+
+// appGalleryListedApp is an app in the card lists returned by the AppGallery web API.
+type appGalleryListedApp struct {
+	// Score is sent as a string or a number depending on the card type
+	Score     any    `json:"score"`
+	AppID     string `json:"appid"`
+	Name      string `json:"name"`
+	Package   string `json:"package"`
+	Icon      string `json:"icon"`
+	KindName  string `json:"kindName"`
+	Downloads string `json:"downCountDesc"`
+	Developer string `json:"developerName"`
+	Version   string `json:"versionName"`
+}
+
+// toApp converts a listed app to an App.
+func (a appGalleryListedApp) toApp() App {
+	appID := strings.TrimPrefix(a.AppID, "C")
+	app := App{
+		AppID:     appID,
+		BundleID:  a.Package,
+		URL:       fmt.Sprintf("https://appgallery.huawei.com/app/C%s", appID),
+		Title:     a.Name,
+		Version:   a.Version,
+		Developer: a.Developer,
+		Icon:      a.Icon,
+		Category:  a.KindName,
+		Installs:  strings.TrimSpace(strings.TrimSuffix(a.Downloads, "installs")),
+	}
+	switch score := a.Score.(type) {
+	case float64:
+		app.Rating = score
+	case string:
+		app.Rating, _ = strconv.ParseFloat(score, 64)
+	}
+	return app
+}
+
+// Search finds apps with the search of the AppGallery website.
+func (AppGallery) Search(ctx context.Context, q SearchQuery) ([]App, error) {
+	params := url.Values{
+		"method":      {"internal.getTabDetail"},
+		"serviceType": {"20"},
+		"reqPageNum":  {"1"},
+		"uri":         {"searchApp|" + q.Term},
+		"locale":      {cmp.Or(q.Lang, "en")},
+		"zone":        {""},
+	}
+	if q.Limit > 0 {
+		params.Set("maxResults", strconv.Itoa(q.Limit))
+	}
+
+	log.Printf("Searching Huawei AppGallery for term: %s", q.Term)
+	apps, err := appGalleryWebList(ctx, appGalleryWebAPI+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit > 0 && len(apps) > q.Limit {
+		apps = apps[:q.Limit]
+	}
+	return apps, nil
+}
+
+// DeveloperApps lists the apps of a developer with the AppGallery website's developer page API.
+func (AppGallery) DeveloperApps(ctx context.Context, q DeveloperQuery) ([]App, error) {
+	if q.ID == "" {
+		return nil, fmt.Errorf("%w: missing developerId", ErrInvalidInput)
+	}
+
+	params := url.Values{
+		"method":      {"internal.getTabDetail"},
+		"serviceType": {"20"},
+		"reqPageNum":  {"1"},
+		"maxResults":  {"100"},
+		"uri":         {"developerAppList|" + q.ID},
+		"locale":      {cmp.Or(q.Lang, "en")},
+		"zone":        {""},
+	}
+
+	log.Printf("Fetching Huawei AppGallery apps of developer: %s", q.ID)
+	apps, err := appGalleryWebList(ctx, appGalleryWebAPI+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("%w: no developer %s", ErrAppNotFound, q.ID)
+	}
+	for i := range apps {
+		apps[i].DeveloperID = q.ID
+	}
+	return apps, nil
+}
+
+// appGalleryWebList fetches the apps listed in the cards of an AppGallery web API response.
+func appGalleryWebList(ctx context.Context, apiURL string) ([]App, error) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", ErrPageLoad, err)
+		}
+		return nil, fmt.Errorf("failed to get app list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app list: %w", err)
+	}
+
+	var response struct {
+		LayoutData []struct {
+			DataList []appGalleryListedApp `json:"dataList"`
+		} `json:"layoutData"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode app list: %w", ErrUpstream, err)
+	}
+
+	apps := []App{}
+	seen := make(map[string]bool)
+	for _, layout := range response.LayoutData {
+		for _, listed := range layout.DataList {
+			// Cards also list banners and links, which carry no app id
+			if listed.AppID == "" || seen[listed.AppID] {
+				continue
+			}
+			seen[listed.AppID] = true
+			apps = append(apps, listed.toApp())
+		}
+	}
+	return apps, nil
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AppStore exposes AppleAppStore through the Store interface.
+type AppStore struct{}
+
+func (AppStore) Name() string { return "appstore" }
+
+func (AppStore) Title() string { return "Apple App Store" }
+
+func (AppStore) Identifiers() []Identifier {
+	return []Identifier{IdentifierAppID, IdentifierBundleID}
+}
+
+func (AppStore) Options() []string { return []string{"country"} }
+
+func (AppStore) Lookup(ctx context.Context, q Query) (App, error) {
+	return AppleAppStore(ctx, q.AppID, q.BundleID, q.Country)
+}
+
+// Search finds apps with the iTunes Search API.
+func (AppStore) Search(ctx context.Context, q SearchQuery) ([]App, error) {
+	params := url.Values{
+		"term":    {q.Term},
+		"country": {cmp.Or(q.Country, "us")},
+		"entity":  {"software"},
+	}
+	if q.Lang != "" {
+		params.Set("lang", q.Lang)
+	}
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	log.Printf("Searching AppleAppStore for term: %s", q.Term)
+	results, err := itunesRequest(ctx, appStoreBaseURL+"/search?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	return itunesApps(results), nil
+}
+
+// appStoreBaseURL is the iTunes lookup API origin, replaced by tests.
+var appStoreBaseURL = "https://itunes.apple.com"
+
+// AppleAppStore fetches an app from the iTunes lookup API by its track ID or bundle ID.
+func AppleAppStore(ctx context.Context, appID, bundleID, country string) (App, error) {
+	if country == "" {
+		country = "us"
+	}
+
+	if bundleID == "" {
+		if err := validateID("appId", appID, numericID); err != nil {
+			return App{}, err
+		}
+	}
+
+	params := url.Values{"country": {country}}
+	if bundleID != "" {
+		params.Set("bundleId", bundleID)
+	} else {
+		params.Set("id", appID)
+	}
+
+	log.Printf("Fetching AppleAppStore app data for appID: %s", appID)
+	results, err := itunesRequest(ctx, appStoreBaseURL+"/lookup?"+params.Encode())
+	if err != nil {
+		return App{}, err
+	}
+
+	if len(results) == 0 {
+		return App{}, ErrAppNotFound
+	}
+	return results[0].toApp()
+}
+
+// itunesResult is an app in the responses of the iTunes lookup and search APIs.
+type itunesResult struct {
+	Version                   string  `json:"version"`
+	CurrentVersionReleaseDate string  `json:"currentVersionReleaseDate"`
+	BundleID                  string  `json:"bundleId"`
+	TrackName                 string  `json:"trackName"`
+	TrackViewURL              string  `json:"trackViewUrl"`
+	ArtistName                string  `json:"artistName"`
+	Currency                  string  `json:"currency"`
+	ArtworkURL512             string  `json:"artworkUrl512"`
+	ContentAdvisoryRating     string  `json:"contentAdvisoryRating"`
+	PrimaryGenreName          string  `json:"primaryGenreName"`
+	MinimumOSVersion          string  `json:"minimumOsVersion"`
+	FileSizeBytes             string  `json:"fileSizeBytes"`
+	ReleaseNotes              string  `json:"releaseNotes"`
+	WrapperType               string  `json:"wrapperType"`
+	AverageUserRating         float64 `json:"averageUserRating"`
+	Price                     float64 `json:"price"`
+	UserRatingCount           int64   `json:"userRatingCount"`
+	TrackID                   int     `json:"trackId"`
+	ArtistID                  int     `json:"artistId"`
+}
+
+// toApp converts an iTunes result to an App.
+func (r itunesResult) toApp() (App, error) {
+	parseDate, err := time.Parse("2006-01-02T15:04:05Z", r.CurrentVersionReleaseDate)
+	if err != nil {
+		return App{}, fmt.Errorf("%w: invalid release date %q: %w", ErrUpstream, r.CurrentVersionReleaseDate, err)
+	}
+
+	app := App{
+		AppID:         strconv.Itoa(r.TrackID),
+		BundleID:      r.BundleID,
+		URL:           r.TrackViewURL,
+		Title:         r.TrackName,
+		Version:       r.Version,
+		Updated:       parseDate.Format("02-01-2006"),
+		Developer:     r.ArtistName,
+		Icon:          r.ArtworkURL512,
+		ContentRating: r.ContentAdvisoryRating,
+		Category:      r.PrimaryGenreName,
+		ReleaseNotes:  r.ReleaseNotes,
+		MinOSVersion:  r.MinimumOSVersion,
+		Rating:        r.AverageUserRating,
+		RatingCount:   r.UserRatingCount,
+	}
+	// Results without an artist would otherwise point at developer 0
+	if r.ArtistID != 0 {
+		app.DeveloperID = strconv.Itoa(r.ArtistID)
+	}
+	if r.Price > 0 {
+		app.Price = r.Price
+		app.Currency = r.Currency
+	}
+	// The file size is the only number iTunes sends as a string
+	if size, err := strconv.ParseInt(r.FileSizeBytes, 10, 64); err == nil {
+		app.Size = size
+	}
+	return app, nil
+}
+
+// DeveloperApps lists the apps of an artist with the iTunes lookup API.
+func (AppStore) DeveloperApps(ctx context.Context, q DeveloperQuery) ([]App, error) {
+	if err := validateID("developerId", q.ID, numericID); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"id":      {q.ID},
+		"country": {cmp.Or(q.Country, "us")},
+		"entity":  {"software"},
+		"limit":   {"200"},
+	}
+
+	log.Printf("Fetching AppleAppStore apps of developer: %s", q.ID)
+	results, err := itunesRequest(ctx, appStoreBaseURL+"/lookup?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	// The artist itself is the first result
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%w: no developer %s", ErrAppNotFound, q.ID)
+	}
+	return itunesApps(results), nil
+}
+
+// itunesApps converts a list of iTunes results, skipping artists and results
+// that are not valid apps.
+func itunesApps(results []itunesResult) []App {
+	apps := make([]App, 0, len(results))
+	for _, r := range results {
+		if r.WrapperType != "" && r.WrapperType != "software" {
+			continue
+		}
+		app, err := r.toApp()
+		if err != nil {
+			continue
+		}
+		apps = append(apps, app)
+	}
+	return apps
+}
+
+// itunesRequest fetches the results of an iTunes lookup or search API URL.
+func itunesRequest(ctx context.Context, itunesURL string) ([]itunesResult, error) {
+	var response struct {
+		Results     []itunesResult `json:"results"`
+		ResultCount int            `json:"resultCount"`
+	}
+	if err := fetchJSON(ctx, itunesURL, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
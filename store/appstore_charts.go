@@ -0,0 +1,142 @@
+package store
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// itunesFeeds maps chart names to the feeds of the iTunes RSS generator.
+var itunesFeeds = map[string]string{
+	ChartTopFree:     "topfreeapplications",
+	ChartTopPaid:     "toppaidapplications",
+	ChartTopGrossing: "topgrossingapplications",
+}
+
+// maxITunesFeedLimit is the most entries an iTunes RSS feed returns
+const maxITunesFeedLimit = 200
+
+// itunesLabel is how the iTunes RSS feeds encode text values.
+type itunesLabel struct {
+	Label string `json:"label"`
+}
+
+// itunesFeedEntry is an app in an iTunes RSS feed.
+type itunesFeedEntry struct {
+	ID struct {
+		Attributes struct {
+			ID       string `json:"im:id"`
+			BundleID string `json:"im:bundleId"`
+		} `json:"attributes"`
+	} `json:"id"`
+	Link struct {
+		Attributes struct {
+			Href string `json:"href"`
+		} `json:"attributes"`
+	} `json:"link"`
+	Category struct {
+		Attributes struct {
+			Label string `json:"label"`
+		} `json:"attributes"`
+	} `json:"category"`
+	Price struct {
+		Attributes struct {
+			Amount   string `json:"amount"`
+			Currency string `json:"currency"`
+		} `json:"attributes"`
+	} `json:"im:price"`
+	Name   itunesLabel   `json:"im:name"`
+	Artist itunesLabel   `json:"im:artist"`
+	Images []itunesLabel `json:"im:image"`
+}
+
+// toApp converts a feed entry to an App.
+func (e itunesFeedEntry) toApp() App {
+	app := App{
+		AppID:     e.ID.Attributes.ID,
+		BundleID:  e.ID.Attributes.BundleID,
+		URL:       e.Link.Attributes.Href,
+		Title:     e.Name.Label,
+		Developer: e.Artist.Label,
+		Category:  e.Category.Attributes.Label,
+	}
+	// Images are listed smallest first
+	if len(e.Images) > 0 {
+		app.Icon = e.Images[len(e.Images)-1].Label
+	}
+	if price, err := strconv.ParseFloat(e.Price.Attributes.Amount, 64); err == nil && price > 0 {
+		app.Price = price
+		app.Currency = e.Price.Attributes.Currency
+	}
+	return app
+}
+
+// Chart fetches a top chart from the iTunes RSS feeds. Categories are App
+// Store genre ids such as "6014" for games.
+func (AppStore) Chart(ctx context.Context, q ChartQuery) ([]App, error) {
+	feed, ok := itunesFeeds[q.Chart]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown chart %q", ErrInvalidInput, q.Chart)
+	}
+	if err := validateID("category", q.Category, numericID); err != nil {
+		return nil, err
+	}
+	// The country is part of the feed path
+	if err := validateID("country", q.Country, countryCode); err != nil {
+		return nil, err
+	}
+
+	limit := q.Limit
+	if limit <= 0 || limit > maxITunesFeedLimit {
+		limit = maxITunesFeedLimit
+	}
+
+	feedURL := fmt.Sprintf("%s/%s/rss/%s/limit=%d", appStoreBaseURL, cmp.Or(q.Country, "us"), feed, limit)
+	if q.Category != "" {
+		feedURL += "/genre=" + q.Category
+	}
+	feedURL += "/json"
+
+	log.Printf("Fetching AppleAppStore %s chart, category: %q", q.Chart, q.Category)
+	var response struct {
+		Feed struct {
+			Entry json.RawMessage `json:"entry"`
+		} `json:"feed"`
+	}
+	if err := fetchJSON(ctx, feedURL, &response); err != nil {
+		return nil, err
+	}
+
+	entries, err := itunesFeedEntries[itunesFeedEntry](response.Feed.Entry)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := make([]App, 0, len(entries))
+	for _, e := range entries {
+		apps = append(apps, e.toApp())
+	}
+	return apps, nil
+}
+
+// itunesFeedEntries decodes the entries of a feed, which are a single object
+// rather than a list when the feed has one entry.
+func itunesFeedEntries[T any](raw json.RawMessage) ([]T, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return []T{}, nil
+	}
+	if raw[0] == '{' {
+		raw = append(append([]byte{'['}, raw...), ']')
+	}
+
+	var entries []T
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode feed entries: %w", ErrUpstream, err)
+	}
+	return entries, nil
+}
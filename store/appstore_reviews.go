@@ -0,0 +1,117 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// maxITunesReviewPage is the last page the customer reviews feed serves
+const maxITunesReviewPage = 10
+
+// itunesReviewSorts maps review sort orders to the sortby values of the customer reviews feed.
+var itunesReviewSorts = map[string]string{
+	SortNewest:  "mostrecent",
+	SortHelpful: "mosthelpful",
+}
+
+// itunesReviewEntry is a review in the customer reviews feed.
+type itunesReviewEntry struct {
+	Author struct {
+		Name itunesLabel `json:"name"`
+	} `json:"author"`
+	Updated itunesLabel `json:"updated"`
+	Rating  itunesLabel `json:"im:rating"`
+	Version itunesLabel `json:"im:version"`
+	ID      itunesLabel `json:"id"`
+	Title   itunesLabel `json:"title"`
+	Content itunesLabel `json:"content"`
+}
+
+// toReview converts a feed entry to a Review.
+func (e itunesReviewEntry) toReview() (Review, error) {
+	rating, err := strconv.Atoi(e.Rating.Label)
+	if err != nil {
+		return Review{}, fmt.Errorf("invalid rating %q", e.Rating.Label)
+	}
+	date, err := time.Parse(time.RFC3339, e.Updated.Label)
+	if err != nil {
+		return Review{}, fmt.Errorf("invalid date %q", e.Updated.Label)
+	}
+	return Review{
+		Date:    date.UTC(),
+		ID:      e.ID.Label,
+		Author:  e.Author.Name.Label,
+		Title:   e.Title.Label,
+		Body:    e.Content.Label,
+		Version: e.Version.Label,
+		Rating:  rating,
+	}, nil
+}
+
+// Reviews fetches a page of the iTunes customer reviews feed. The feed pages
+// by number and stops after page 10; it does not carry developer replies.
+func (AppStore) Reviews(ctx context.Context, q ReviewQuery) (ReviewPage, error) {
+	if q.ID == "" {
+		return ReviewPage{}, fmt.Errorf("%w: missing appId", ErrInvalidInput)
+	}
+	if err := validateID("appId", q.ID, numericID); err != nil {
+		return ReviewPage{}, err
+	}
+	// The country is part of the feed path
+	if err := validateID("country", q.Country, countryCode); err != nil {
+		return ReviewPage{}, err
+	}
+	sortBy, ok := itunesReviewSorts[cmp.Or(q.Sort, SortNewest)]
+	if !ok {
+		return ReviewPage{}, fmt.Errorf("%w: unknown sort %q", ErrInvalidInput, q.Sort)
+	}
+
+	page := 1
+	if q.Page != "" {
+		state, err := decodePageToken(q.Page)
+		if err != nil {
+			return ReviewPage{}, err
+		}
+		page, err = strconv.Atoi(state)
+		if err != nil || page < 1 || page > maxITunesReviewPage {
+			return ReviewPage{}, fmt.Errorf("%w: malformed page token %q", ErrInvalidInput, q.Page)
+		}
+	}
+
+	feedURL := fmt.Sprintf("%s/%s/rss/customerreviews/page=%d/id=%s/sortby=%s/json",
+		appStoreBaseURL, cmp.Or(q.Country, "us"), page, q.ID, sortBy)
+
+	log.Printf("Fetching AppleAppStore reviews for app ID: %s, page: %d", q.ID, page)
+	var response struct {
+		Feed struct {
+			Entry json.RawMessage `json:"entry"`
+		} `json:"feed"`
+	}
+	if err := fetchJSON(ctx, feedURL, &response); err != nil {
+		return ReviewPage{}, err
+	}
+
+	entries, err := itunesFeedEntries[itunesReviewEntry](response.Feed.Entry)
+	if err != nil {
+		return ReviewPage{}, err
+	}
+
+	result := ReviewPage{Reviews: make([]Review, 0, len(entries))}
+	for _, e := range entries {
+		// Older feeds open with an entry describing the app itself, which has no rating
+		review, err := e.toReview()
+		if err != nil {
+			continue
+		}
+		result.Reviews = append(result.Reviews, review)
+	}
+	if len(entries) > 0 && page < maxITunesReviewPage {
+		result.NextPage = encodePageToken(strconv.Itoa(page + 1))
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveAppStoreFixtures serves the saved lookup responses in testdata/appstore
+// in place of the iTunes lookup API for the duration of the test
+func serveAppStoreFixtures(t *testing.T) {
+	t.Helper()
+
+	feeds := map[string]string{
+		"/us/rss/topfreeapplications/limit=200/genre=6014/json":                 "topfree.json",
+		"/us/rss/toppaidapplications/limit=1/json":                              "toppaid.json",
+		"/us/rss/customerreviews/page=1/id=1592213654/sortby=mostrecent/json":   "reviews.json",
+		"/gb/rss/customerreviews/page=10/id=1592213654/sortby=mosthelpful/json": "reviews.json",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture := "empty.json"
+		if feed, ok := feeds[r.URL.Path]; ok {
+			fixture = feed
+		}
+		query := r.URL.Query()
+		if query.Get("entity") == "software" && query.Get("id") == "1592213653" {
+			fixture = "developer.json"
+		} else if query.Get("id") == "1592213654" || query.Get("bundleId") == "com.arise.katsini" || query.Get("term") == "katsini" {
+			fixture = "lookup.json"
+		}
+		page, err := os.ReadFile(filepath.Join("testdata", "appstore", fixture))
+		if err != nil {
+			t.Errorf("Failed to read fixture: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		_, _ = w.Write(page)
+	}))
+	t.Cleanup(srv.Close)
+
+	original := appStoreBaseURL
+	appStoreBaseURL = srv.URL
+	t.Cleanup(func() { appStoreBaseURL = original })
+}
+
+func TestAppleAppStoreMetadata(t *testing.T) {
+	serveAppStoreFixtures(t)
+
+	want := App{
+		AppID:     "1592213654",
+		BundleID:  "com.arise.katsini",
+		URL:       "https://apps.apple.com/us/app/katsini-demo/id1592213654?uo=4",
+		Title:     "Katsini Demo",
+		Version:   "2.4.1",
+		Updated:   "21-05-2024",
+		Developer: "Arise",
+
+		DeveloperID:   "1592213653",
+		Currency:      "USD",
+		Icon:          "https://is1-ssl.mzstatic.com/image/thumb/Purple/512x512bb.jpg",
+		ContentRating: "4+",
+		Category:      "Utilities",
+		ReleaseNotes:  "Bug fixes.",
+		MinOSVersion:  "15.0",
+		Rating:        4.61538,
+		RatingCount:   1300,
+		Price:         4.99,
+		Size:          48379904,
+	}
+
+	app, err := AppleAppStore(context.Background(), "1592213654", "", "us")
+	require.NoError(t, err)
+	assert.Equal(t, want, app)
+
+	app, err = AppleAppStore(context.Background(), "", "com.arise.katsini", "")
+	require.NoError(t, err)
+	assert.Equal(t, want, app)
+
+	_, err = AppleAppStore(context.Background(), "1", "", "us")
+	assert.ErrorIs(t, err, ErrAppNotFound)
+
+	app, err = itunesResult{TrackID: 1, CurrentVersionReleaseDate: "2024-05-21T07:00:00Z"}.toApp()
+	require.NoError(t, err)
+	assert.Empty(t, app.DeveloperID, "results without an artist have no developer")
+}
+
+func TestAppleAppStoreErrors(t *testing.T) {
+	var query url.Values
+	body := `{"resultCount":1,"results":[{"trackId":1,"currentVersionReleaseDate":"yesterday"}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	original := appStoreBaseURL
+	appStoreBaseURL = srv.URL
+	t.Cleanup(func() { appStoreBaseURL = original })
+
+	_, err := AppleAppStore(context.Background(), "", "com.example&id=1#", "de&entity=x")
+	assert.ErrorIs(t, err, ErrUpstream, "an invalid release date is an upstream error")
+	assert.Equal(t, url.Values{"bundleId": {"com.example&id=1#"}, "country": {"de&entity=x"}}, query)
+
+	body = "{"
+	_, err = AppleAppStore(context.Background(), "1", "", "us")
+	assert.ErrorIs(t, err, ErrUpstream)
+
+	srv.Close()
+	_, err = AppleAppStore(context.Background(), "1", "", "us")
+	assert.ErrorIs(t, err, ErrPageLoad)
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pageInfo is what the page classifiers see of a page loaded in the browser.
+type pageInfo struct {
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Text    string `json:"text"`
+	Captcha bool   `json:"captcha"`
+}
+
+// pageInfoScript collects the pageInfo of the current page. The body text is
+// truncated, since interstitials put their message at the top.
+const pageInfoScript = `({
+	url: location.href,
+	title: document.title,
+	text: (document.body?.innerText || '').slice(0, 4000),
+	captcha: !!document.querySelector('iframe[src*="recaptcha"], .g-recaptcha, #captcha-form, [class*="captcha"], [id*="captcha"]')
+})`
+
+// pageRule recognises a page that is not the requested content.
+type pageRule struct {
+	match  func(p pageInfo) bool
+	reason string
+}
+
+// classifyPage returns ErrBlocked with the reason of the first matching rule.
+func classifyPage(p pageInfo, rules []pageRule) error {
+	for _, rule := range rules {
+		if rule.match(p) {
+			return fmt.Errorf("%w: %s at %s", ErrBlocked, rule.reason, p.URL)
+		}
+	}
+	return nil
+}
+
+// textContains returns a rule matcher looking for any of the phrases in the page text.
+func textContains(phrases ...string) func(p pageInfo) bool {
+	return func(p pageInfo) bool {
+		text := strings.ToLower(p.Text)
+		for _, phrase := range phrases {
+			if strings.Contains(text, strings.ToLower(phrase)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// playStorePageRules recognise Google's rate limiting, captcha and consent interstitials.
+var playStorePageRules = []pageRule{
+	{
+		reason: "captcha page",
+		match: func(p pageInfo) bool {
+			return p.Captcha || strings.Contains(p.URL, "google.com/sorry/")
+		},
+	},
+	{
+		reason: "unusual traffic page",
+		match: textContains(
+			"Our systems have detected unusual traffic",
+			"unusual traffic from your computer network",
+		),
+	},
+	{
+		reason: "consent page",
+		match: func(p pageInfo) bool {
+			return strings.Contains(p.URL, "consent.google.") ||
+				textContains("Before you continue to Google", "Bevor Sie zu Google weitergehen", "Avant d'accéder à Google")(p)
+		},
+	},
+}
+
+// playStoreBlockError returns ErrBlocked when a Play page is an interstitial.
+// Store pages embed their data in AF_initDataCallback blocks, which
+// interstitials lack, so a page with data is never classified; an app
+// description quoting a block phrase does not turn it into a block.
+func playStoreBlockError(p pageInfo, html string) error {
+	if _, err := initDataBlocks(html); err == nil {
+		return nil
+	}
+	return classifyPage(p, playStorePageRules)
+}
+
+// appGalleryPageRules recognise Huawei's verification and access denied pages.
+var appGalleryPageRules = []pageRule{
+	{
+		reason: "captcha page",
+		match: func(p pageInfo) bool {
+			return p.Captcha || textContains("slide to verify", "drag the slider", "security verification")(p)
+		},
+	},
+	{
+		reason: "access denied page",
+		match:  textContains("Access Denied", "403 Forbidden", "Request blocked"),
+	},
+}
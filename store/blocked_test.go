@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyPlayStorePage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		page     pageInfo
+		expected string
+	}{
+		{
+			name: "App page",
+			page: pageInfo{URL: "https://play.google.com/store/apps/details?id=com.mediocre.dirac", Text: "Beyondium\nMediocre\nAbout this game"},
+		},
+		{
+			name:     "Sorry page",
+			page:     pageInfo{URL: "https://www.google.com/sorry/index?continue=https://play.google.com/"},
+			expected: "blocked by store: captcha page at https://www.google.com/sorry/index?continue=https://play.google.com/",
+		},
+		{
+			name:     "Recaptcha",
+			page:     pageInfo{URL: "https://play.google.com/store/apps/details?id=x.y", Captcha: true},
+			expected: "blocked by store: captcha page at https://play.google.com/store/apps/details?id=x.y",
+		},
+		{
+			name:     "Unusual traffic",
+			page:     pageInfo{URL: "https://play.google.com/", Text: "Our systems have detected unusual traffic from your computer network."},
+			expected: "blocked by store: unusual traffic page at https://play.google.com/",
+		},
+		{
+			name:     "Consent redirect",
+			page:     pageInfo{URL: "https://consent.google.com/m?continue=https://play.google.com/"},
+			expected: "blocked by store: consent page at https://consent.google.com/m?continue=https://play.google.com/",
+		},
+		{
+			name:     "Localized consent",
+			page:     pageInfo{URL: "https://play.google.com/", Text: "Bevor Sie zu Google weitergehen"},
+			expected: "blocked by store: consent page at https://play.google.com/",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyPage(tt.page, playStorePageRules)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrBlocked)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestPlayStoreBlockError(t *testing.T) {
+	phrase := "Our systems have detected unusual traffic from your computer network."
+	interstitial := "<html><body>" + phrase + "</body></html>"
+	assert.ErrorIs(t, playStoreBlockError(pageInfo{Text: interstitial}, interstitial), ErrBlocked)
+
+	// An app description may quote the phrase without the page being a block
+	page := `<html><body><script>AF_initDataCallback({key: 'ds:5', hash: '1', data:[["` + phrase + `"]], sideChannel: {}});</script></body></html>`
+	assert.NoError(t, playStoreBlockError(pageInfo{Text: page}, page))
+}
+
+func TestClassifyAppGalleryPage(t *testing.T) {
+	assert.NoError(t, classifyPage(pageInfo{Text: "5G Checker\nScriptRepublic"}, appGalleryPageRules))
+	assert.ErrorIs(t, classifyPage(pageInfo{Text: "Please slide to verify"}, appGalleryPageRules), ErrBlocked)
+	assert.ErrorIs(t, classifyPage(pageInfo{Text: "403 Forbidden"}, appGalleryPageRules), ErrBlocked)
+}
+
+func TestAppGalleryMissingError(t *testing.T) {
+	assert.ErrorIs(t, appGalleryMissingError("", "https://appgallery.huawei.com/app/C1"), ErrBlocked)
+	assert.ErrorIs(t, appGalleryMissingError("This app is no longer available", "https://appgallery.huawei.com/app/C1"), ErrAppNotFound)
+}
+
+func TestRetryOperationSkipsBlocked(t *testing.T) {
+	attempts := 0
+	_, err := retryOperation(context.Background(), func(context.Context) (App, error) {
+		attempts++
+		return App{}, classifyPage(pageInfo{Captcha: true}, appGalleryPageRules)
+	}, 3, "test")
+
+	assert.ErrorIs(t, err, ErrBlocked)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+
+	undetected "github.com/Davincible/chromedp-undetected"
+	"github.com/chromedp/chromedp"
+)
+
+// Common resource types to block for faster page loading
+var commonResourceTypesToBlock = []network.ResourceType{
+	network.ResourceTypeImage,
+	network.ResourceTypeFont,
+	network.ResourceTypeMedia,
+	network.ResourceTypeManifest,
+	network.ResourceTypeOther,
+}
+
+// createBrowserContext starts a browser with anti-bot protection, bound to ctx; the pool opens tabs on it
+// It automatically detects whether to use local Chrome or remote Chrome based on environment variables
+func createBrowserContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	chromeHost := os.Getenv("CHROME_HOST")
+	chromePort := os.Getenv("CHROME_PORT")
+
+	// If CHROME_HOST and CHROME_PORT are set, use remote Chrome (for backward compatibility with tests)
+	if chromeHost != "" && chromePort != "" {
+		log.Printf("Using remote Chrome at %s:%s", chromeHost, chromePort)
+		// Try undetected mode with remote Chrome
+		taskCtx, cancel, err := undetected.New(undetected.Config{
+			Ctx:        ctx,
+			ChromePath: "ws://" + chromeHost + ":" + chromePort,
+			Headless:   true,
+			NoSandbox:  true,
+		})
+		if err != nil {
+			log.Printf("Undetected mode not available, falling back to regular chromedp: %v", err)
+			// Fallback to regular chromedp
+			allocCtx, allocCancel := chromedp.NewRemoteAllocator(ctx, fmt.Sprintf("ws://%s:%s/json", chromeHost, chromePort))
+			taskCtx, cancel = chromedp.NewContext(allocCtx)
+			// Return a combined cancel function
+			return taskCtx, func() {
+				cancel()
+				allocCancel()
+			}, nil
+		}
+		return taskCtx, cancel, nil
+	}
+
+	// Use local Chrome with chromedp-undetected
+	log.Printf("Using local Chrome with chromedp-undetected")
+	taskCtx, cancel, err := undetected.New(undetected.Config{
+		Ctx:       ctx,
+		Headless:  true,
+		NoSandbox: true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create undetected context: %w", err)
+	}
+	return taskCtx, cancel, nil
+}
+
+func DisableFetchExceptScripts(ctx context.Context, resourceTypesToBlock []network.ResourceType) func(event any) {
+	return func(event any) {
+		if ev, ok := event.(*fetch.EventRequestPaused); ok {
+			go func() {
+				c := chromedp.FromContext(ctx)
+				cdpCtx := cdp.WithExecutor(ctx, c.Target)
+
+				shouldBlock := false
+				for _, resourceType := range resourceTypesToBlock {
+					if ev.ResourceType == resourceType {
+						shouldBlock = true
+						break
+					}
+				}
+
+				if shouldBlock {
+					if err := fetch.FailRequest(ev.RequestID, network.ErrorReasonBlockedByClient).Do(cdpCtx); err != nil {
+						log.Printf("Failed to block request: %s \n", err)
+						return
+					}
+				} else {
+					if err := fetch.ContinueRequest(ev.RequestID).Do(cdpCtx); err != nil {
+						log.Printf("Failed to continue request: %s \n", err)
+						return
+					}
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package store
+
+import "context"
+
+// Chart names accepted by every Charter.
+const (
+	ChartTopFree     = "top-free"
+	ChartTopPaid     = "top-paid"
+	ChartTopGrossing = "top-grossing"
+)
+
+// Charts lists the chart names in display order.
+var Charts = []string{ChartTopFree, ChartTopPaid, ChartTopGrossing}
+
+// ChartQuery holds the parameters of a top chart.
+type ChartQuery struct {
+	// Chart is one of Charts
+	Chart string
+	// Category is the store's own category identifier, e.g. "6014" on the
+	// App Store or "GAME_ACTION" on Google Play; empty for the overall chart
+	Category string
+	Lang     string
+	Country  string
+	Limit    int
+}
+
+// Charter is implemented by stores publishing top charts. Apps are returned
+// in rank order, first place first.
+type Charter interface {
+	Chart(ctx context.Context, q ChartQuery) ([]App, error)
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppStoreChart(t *testing.T) {
+	serveAppStoreFixtures(t)
+
+	apps, err := AppStore{}.Chart(context.Background(), ChartQuery{Chart: ChartTopFree, Category: "6014"})
+	require.NoError(t, err)
+	require.Len(t, apps, 2)
+	assert.Equal(t, App{
+		AppID:     "1482155847",
+		BundleID:  "com.block.juggle",
+		URL:       "https://apps.apple.com/us/app/id1482155847?uo=2",
+		Title:     "Block Blast!",
+		Developer: "Hungry Studio",
+		Icon:      "https://is1-ssl.mzstatic.com/1482155847/100x100bb.png",
+		Category:  "Games",
+	}, apps[0])
+	assert.Equal(t, "Royal Match", apps[1].Title)
+
+	// Feeds with a single entry send an object instead of a list
+	apps, err = AppStore{}.Chart(context.Background(), ChartQuery{Chart: ChartTopPaid, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "Minecraft", apps[0].Title)
+	assert.InDelta(t, 6.99, apps[0].Price, 0.001)
+	assert.Equal(t, "USD", apps[0].Currency)
+
+	_, err = AppStore{}.Chart(context.Background(), ChartQuery{Chart: "top-new"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = AppStore{}.Chart(context.Background(), ChartQuery{Chart: ChartTopFree, Category: "games"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = AppStore{}.Chart(context.Background(), ChartQuery{Chart: ChartTopFree, Country: "us/rss/x"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestPlayStoreChart(t *testing.T) {
+	servePlayStoreFixtures(t)
+
+	apps, err := PlayStore{}.Chart(context.Background(), ChartQuery{Chart: ChartTopFree, Category: "GAME", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, apps, 2)
+	assert.Equal(t, App{
+		BundleID:  "com.radio.fmradio",
+		URL:       "https://play.google.com/store/apps/details?id=com.radio.fmradio&hl=en&gl=us",
+		Title:     "Radio FM",
+		Developer: "RADIOFM",
+		Icon:      "https://play-lh.googleusercontent.com/radiofm-icon",
+		Rating:    4.6,
+	}, apps[0])
+	assert.InDelta(t, 2.99, apps[1].Price, 0.001)
+	assert.Equal(t, "USD", apps[1].Currency)
+
+	_, err = PlayStore{}.Chart(context.Background(), ChartQuery{Chart: ChartTopPaid})
+	assert.ErrorIs(t, err, ErrAppNotFound)
+
+	_, err = PlayStore{}.Chart(context.Background(), ChartQuery{Chart: ChartTopFree, Category: "game/../x"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
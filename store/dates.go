@@ -0,0 +1,291 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// monthNames lists the month names used in dates per base language, January
+// first. Alternatives such as abbreviations and the genitive forms used in
+// dates are separated by "|"; trailing periods are ignored.
+var monthNames = map[string][12]string{
+	"en": {"january|jan", "february|feb", "march|mar", "april|apr", "may", "june|jun", "july|jul", "august|aug", "september|sep|sept", "october|oct", "november|nov", "december|dec"},
+	"de": {"januar|jan|jänner|jän", "februar|feb", "märz|mär|mrz", "april|apr", "mai", "juni|jun", "juli|jul", "august|aug", "september|sep|sept", "oktober|okt", "november|nov", "dezember|dez"},
+	"fr": {"janvier|janv", "février|févr|fevrier", "mars", "avril|avr", "mai", "juin", "juillet|juil", "août|aout", "septembre|sept", "octobre|oct", "novembre|nov", "décembre|déc|decembre"},
+	"es": {"enero|ene", "febrero|feb", "marzo|mar", "abril|abr", "mayo|may", "junio|jun", "julio|jul", "agosto|ago", "septiembre|setiembre|sept|sep", "octubre|oct", "noviembre|nov", "diciembre|dic"},
+	"pt": {"janeiro|jan", "fevereiro|fev", "março|mar", "abril|abr", "maio|mai", "junho|jun", "julho|jul", "agosto|ago", "setembro|set", "outubro|out", "novembro|nov", "dezembro|dez"},
+	"it": {"gennaio|gen", "febbraio|feb", "marzo|mar", "aprile|apr", "maggio|mag", "giugno|giu", "luglio|lug", "agosto|ago", "settembre|set", "ottobre|ott", "novembre|nov", "dicembre|dic"},
+	"nl": {"januari|jan", "februari|feb", "maart|mrt", "april|apr", "mei", "juni|jun", "juli|jul", "augustus|aug", "september|sep", "oktober|okt", "november|nov", "december|dec"},
+	"sv": {"januari|jan", "februari|feb", "mars|mar", "april|apr", "maj", "juni|jun", "juli|jul", "augusti|aug", "september|sep", "oktober|okt", "november|nov", "december|dec"},
+	"da": {"januar|jan", "februar|feb", "marts|mar", "april|apr", "maj", "juni|jun", "juli|jul", "august|aug", "september|sep", "oktober|okt", "november|nov", "december|dec"},
+	"nb": {"januar|jan", "februar|feb", "mars|mar", "april|apr", "mai", "juni|jun", "juli|jul", "august|aug", "september|sep", "oktober|okt", "november|nov", "desember|des"},
+	"fi": {
+		"tammikuuta|tammikuu|tammik", "helmikuuta|helmikuu|helmik", "maaliskuuta|maaliskuu|maalisk", "huhtikuuta|huhtikuu|huhtik",
+		"toukokuuta|toukokuu|toukok", "kesäkuuta|kesäkuu|kesäk", "heinäkuuta|heinäkuu|heinäk", "elokuuta|elokuu|elok",
+		"syyskuuta|syyskuu|syysk", "lokakuuta|lokakuu|lokak", "marraskuuta|marraskuu|marrask", "joulukuuta|joulukuu|jouluk",
+	},
+	"pl": {
+		"stycznia|styczeń|sty", "lutego|luty|lut", "marca|marzec|mar", "kwietnia|kwiecień|kwi", "maja|maj", "czerwca|czerwiec|cze",
+		"lipca|lipiec|lip", "sierpnia|sierpień|sie", "września|wrzesień|wrz", "października|październik|paź", "listopada|listopad|lis", "grudnia|grudzień|gru",
+	},
+	"cs": {
+		"ledna|leden|led", "února|únor|úno", "března|březen|bře", "dubna|duben|dub", "května|květen|kvě", "června|červen|čvn",
+		"července|červenec|čvc", "srpna|srpen|srp", "září|zář", "října|říjen|říj", "listopadu|listopad|lis", "prosince|prosinec|pro",
+	},
+	"ro": {"ianuarie|ian", "februarie|feb", "martie|mar", "aprilie|apr", "mai", "iunie|iun", "iulie|iul", "august|aug", "septembrie|sept|sep", "octombrie|oct", "noiembrie|nov|noi", "decembrie|dec"},
+	"hu": {"január|jan", "február|febr|feb", "március|márc|mar", "április|ápr", "május|máj", "június|jún", "július|júl", "augusztus|aug", "szeptember|szept", "október|okt", "november|nov", "december|dec"},
+	"tr": {"ocak|oca", "şubat|şub", "mart|mar", "nisan|nis", "mayıs|may", "haziran|haz", "temmuz|tem", "ağustos|ağu", "eylül|eyl", "ekim|eki", "kasım|kas", "aralık|ara"},
+	"id": {"januari|jan", "februari|feb", "maret|mar", "april|apr", "mei", "juni|jun", "juli|jul", "agustus|agu|agt", "september|sep", "oktober|okt", "november|nov", "desember|des"},
+	"ms": {"januari|jan", "februari|feb", "mac", "april|apr", "mei", "jun", "julai|jul", "ogos|ogo", "september|sep", "oktober|okt", "november|nov", "disember|dis"},
+	"ru": {
+		"января|январь|янв", "февраля|февраль|февр|фев", "марта|март|мар", "апреля|апрель|апр", "мая|май", "июня|июнь|июн",
+		"июля|июль|июл", "августа|август|авг", "сентября|сентябрь|сент|сен", "октября|октябрь|окт", "ноября|ноябрь|нояб|ноя", "декабря|декабрь|дек",
+	},
+	"uk": {
+		"січня|січень|січ", "лютого|лютий|лют", "березня|березень|бер", "квітня|квітень|квіт|кві", "травня|травень|трав|тра", "червня|червень|черв|чер",
+		"липня|липень|лип", "серпня|серпень|серп|сер", "вересня|вересень|вер", "жовтня|жовтень|жовт|жов", "листопада|листопад|лист|лис", "грудня|грудень|груд|гру",
+	},
+	"el": {
+		"ιανουαρίου|ιανουάριος|ιαν", "φεβρουαρίου|φεβρουάριος|φεβ", "μαρτίου|μάρτιος|μαρ", "απριλίου|απρίλιος|απρ", "μαΐου|μάιος|μαΐ|μαϊ", "ιουνίου|ιούνιος|ιουν",
+		"ιουλίου|ιούλιος|ιουλ", "αυγούστου|αύγουστος|αυγ", "σεπτεμβρίου|σεπτέμβριος|σεπ", "οκτωβρίου|οκτώβριος|οκτ", "νοεμβρίου|νοέμβριος|νοε", "δεκεμβρίου|δεκέμβριος|δεκ",
+	},
+	"ar": {"يناير", "فبراير", "مارس", "أبريل|ابريل|إبريل", "مايو", "يونيو", "يوليو", "أغسطس|اغسطس", "سبتمبر", "أكتوبر|اكتوبر", "نوفمبر", "ديسمبر"},
+	"he": {
+		"בינואר|ינואר|בינו|ינו", "בפברואר|פברואר|בפבר|פבר", "במרץ|מרץ", "באפריל|אפריל|באפר|אפר", "במאי|מאי", "ביוני|יוני",
+		"ביולי|יולי", "באוגוסט|אוגוסט|באוג|אוג", "בספטמבר|ספטמבר|בספט|ספט", "באוקטובר|אוקטובר|באוק|אוק", "בנובמבר|נובמבר|בנוב|נוב", "בדצמבר|דצמבר|בדצמ|דצמ",
+	},
+	"hi": {
+		"जनवरी|जन", "फ़रवरी|फरवरी|फ़र|फर", "मार्च", "अप्रैल", "मई", "जून",
+		"जुलाई|जुल", "अगस्त|अग", "सितंबर|सितम्बर|सित", "अक्तूबर|अक्टूबर|अक्तू|अक्टू", "नवंबर|नवम्बर|नव", "दिसंबर|दिसम्बर|दिस",
+	},
+	"th": {
+		"มกราคม|มค", "กุมภาพันธ์|กพ", "มีนาคม|มีค", "เมษายน|เมย", "พฤษภาคม|พค", "มิถุนายน|มิย",
+		"กรกฎาคม|กค", "สิงหาคม|สค", "กันยายน|กย", "ตุลาคม|ตค", "พฤศจิกายน|พย", "ธันวาคม|ธค",
+	},
+}
+
+// dateOrder is the order of day, month and year in numeric dates.
+type dateOrder int
+
+const (
+	orderDMY dateOrder = iota
+	orderMDY
+	orderYMD
+)
+
+// dateOrders lists the locales not writing numeric dates day first. Year first
+// dates are recognised by their four digit year regardless of the locale.
+var dateOrders = map[string]dateOrder{
+	"en":    orderMDY,
+	"en-us": orderMDY,
+	"fil":   orderMDY,
+	"ja":    orderYMD,
+	"ko":    orderYMD,
+	"zh":    orderYMD,
+	"hu":    orderYMD,
+	"lt":    orderYMD,
+}
+
+// Bidirectional marks Arabic and Hebrew dates are interspersed with
+var bidiMarks = strings.NewReplacer("\u200e", "", "\u200f", "", "\u061c", "")
+
+// monthIndex maps every month name to its month, per base language
+var monthIndex = buildMonthIndex()
+
+// buildMonthIndex indexes monthNames by language and name.
+func buildMonthIndex() map[string]map[string]time.Month {
+	index := make(map[string]map[string]time.Month, len(monthNames))
+	for lang, months := range monthNames {
+		index[lang] = make(map[string]time.Month)
+		for i, names := range months {
+			for name := range strings.SplitSeq(names, "|") {
+				index[lang][name] = time.Month(i + 1)
+			}
+		}
+	}
+	return index
+}
+
+// baseLanguage returns the language of a locale such as "pt-BR" or "zh_TW".
+func baseLanguage(lang string) string {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	base, _, _ := strings.Cut(lang, "-")
+	if base == "no" || base == "nn" {
+		return "nb"
+	}
+	return base
+}
+
+// lookupMonth finds a month name in the language of lang, then in every other language.
+func lookupMonth(word, lang string) (time.Month, bool) {
+	if m, ok := monthIndex[baseLanguage(lang)][word]; ok {
+		return m, true
+	}
+	// Month names are identical or unambiguous across the remaining languages
+	// often enough to help with unknown or mismatched locales
+	for _, names := range []string{"en", "de", "fr", "es", "pt", "it"} {
+		if m, ok := monthIndex[names][word]; ok {
+			return m, true
+		}
+	}
+	for _, index := range monthIndex {
+		if m, ok := index[word]; ok {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// dateTokens splits a date into words and numbers, converting Arabic-Indic
+// digits and dropping punctuation and bidirectional marks. Periods inside a
+// word are dropped as well, joining abbreviations such as the Thai "ต.ค.".
+func dateTokens(s string) (words []string, numbers []string) {
+	s = strings.ToLower(bidiMarks.Replace(s))
+
+	var word, number strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+		if number.Len() > 0 {
+			numbers = append(numbers, number.String())
+			number.Reset()
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '.' && word.Len() > 0 && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+			continue
+		case r >= '٠' && r <= '٩':
+			r = '0' + (r - '٠')
+			fallthrough
+		case r >= '0' && r <= '9':
+			if word.Len() > 0 {
+				flush()
+			}
+			number.WriteRune(r)
+		case r >= '۰' && r <= '۹':
+			if word.Len() > 0 {
+				flush()
+			}
+			number.WriteRune('0' + (r - '۰'))
+		case unicode.IsLetter(r) || unicode.Is(unicode.M, r):
+			if number.Len() > 0 {
+				flush()
+			}
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words, numbers
+}
+
+// parseLocalizedDate parses a date written in the language of lang, such as
+// "31 de out. de 2019", "31. Oktober 2019", "2019年10月31日" or "٣١ أكتوبر ٢٠١٩".
+// Numeric dates follow the locale's day, month and year order, falling back
+// to the other order when the month would be out of range. An empty lang
+// assumes month first dates.
+func parseLocalizedDate(s, lang string) (time.Time, error) {
+	words, numbers := dateTokens(s)
+
+	values := make([]int, 0, len(numbers))
+	for _, n := range numbers {
+		v, err := strconv.Atoi(n)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to parse date '%s': %w", s, err)
+		}
+		values = append(values, v)
+	}
+
+	var month time.Month
+	for _, w := range words {
+		if m, ok := lookupMonth(w, lang); ok {
+			month = m
+			break
+		}
+	}
+
+	var year, day int
+	switch {
+	case month != 0 && len(values) == 2:
+		// Named month: the year is the number that cannot be a day
+		day, year = values[0], values[1]
+		if day > 31 {
+			day, year = year, day
+		}
+	case month == 0 && len(values) == 3:
+		var m int
+		year, m, day = numericDate(values, lang)
+		month = time.Month(m)
+	default:
+		return time.Time{}, fmt.Errorf("unable to parse date '%s' for language '%s'", s, lang)
+	}
+
+	if year < 100 {
+		year += 2000
+	}
+	if baseLanguage(lang) == "th" && year > 2400 {
+		// Thai dates count years in the Buddhist era
+		year -= 543
+	}
+
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day || t.Month() != month || month < time.January || month > time.December {
+		return time.Time{}, fmt.Errorf("unable to parse date '%s': invalid day or month", s)
+	}
+	return t, nil
+}
+
+// numericDate orders the numbers of a date without month names.
+func numericDate(values []int, lang string) (year, month, day int) {
+	if values[0] > 31 {
+		return values[0], values[1], values[2]
+	}
+
+	order, ok := dateOrders[strings.ToLower(lang)]
+	if !ok {
+		order, ok = dateOrders[baseLanguage(lang)]
+	}
+	if !ok && lang == "" {
+		order = orderMDY
+	}
+
+	switch order {
+	case orderYMD:
+		year, month, day = values[0], values[1], values[2]
+	case orderMDY:
+		month, day, year = values[0], values[1], values[2]
+	default:
+		day, month, year = values[0], values[1], values[2]
+	}
+
+	if month > 12 && day <= 12 {
+		month, day = day, month
+	}
+	return year, month, day
+}
+
+// parseFlexibleDate parses a date in one of the common machine formats or,
+// failing that, as a date written in the language of lang.
+func parseFlexibleDate(dateStr, lang string) (time.Time, error) {
+	formats := []string{
+		"2006-01-02",          // ISO format
+		"2006-01-02 15:04:05", // Huawei API format
+		time.RFC3339,
+	}
+
+	for _, format := range formats {
+		if t, err := time.Parse(format, dateStr); err == nil {
+			return t, nil
+		}
+	}
+	return parseLocalizedDate(dateStr, lang)
+}
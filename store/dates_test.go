@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocalizedDate(t *testing.T) {
+	oct31 := time.Date(2019, time.October, 31, 0, 0, 0, 0, time.UTC)
+	may5 := time.Date(2024, time.May, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		want time.Time
+		date string
+		lang string
+	}{
+		{oct31, "Oct 31, 2019", "en"},
+		{oct31, "October 31, 2019", "en-GB"},
+		{oct31, "31.10.2019", "de"},
+		{oct31, "31. Okt. 2019", "de"},
+		{oct31, "31 oct. 2019", "fr"},
+		{oct31, "31 oct 2019", "es"},
+		{oct31, "31 de out. de 2019", "pt-BR"},
+		{oct31, "31 ott 2019", "it"},
+		{oct31, "31 okt. 2019", "nl"},
+		{oct31, "2019年10月31日", "ja"},
+		{oct31, "2019. 10. 31.", "ko"},
+		{oct31, "2019年10月31日", "zh-TW"},
+		{oct31, "‏٣١‏/١٠‏/٢٠١٩", "ar"},
+		{oct31, "٣١ أكتوبر ٢٠١٩", "ar"},
+		{oct31, "31 окт. 2019 г.", "ru"},
+		{oct31, "31 жовт. 2019 р.", "uk"},
+		{oct31, "31 paź 2019", "pl"},
+		{oct31, "31 Eki 2019", "tr"},
+		{oct31, "31 ต.ค. 2562", "th"},
+		{oct31, "31 ตุลาคม 2562", "th"},
+		{oct31, "31 अक्तू॰ 2019", "hi"},
+		{oct31, "31 अक्तूबर 2019", "hi"},
+		{oct31, "31 thg 10, 2019", "vi"},
+		{oct31, "2019. okt. 31.", "hu"},
+		{oct31, "31. lokakuuta 2019", "fi"},
+		{oct31, "31 באוק׳ 2019", "he"},
+		{oct31, "10/31/2019", "en-US"},
+		{oct31, "31/10/2019", "en"},
+		{oct31, "31/10/19", "en-GB"},
+		{may5, "5/5/2024", "fr"},
+		{may5, "5 mai 2024", "ro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.date, func(t *testing.T) {
+			got, err := parseLocalizedDate(tt.date, tt.lang)
+			if tt.want.IsZero() {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseLocalizedDateInvalid(t *testing.T) {
+	for _, date := range []string{"", "yesterday", "32 Oct 2019", "31/13/2019", "Feb 30, 2024", "10/31"} {
+		_, err := parseLocalizedDate(date, "en")
+		assert.Error(t, err, date)
+	}
+}
+
+func TestParseFlexibleDate(t *testing.T) {
+	got, err := parseFlexibleDate("2019-10-31", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.October, 31, 0, 0, 0, 0, time.UTC), got)
+
+	got, err = parseFlexibleDate("2019-10-31 08:15:00", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2019, time.October, 31, 8, 15, 0, 0, time.UTC), got)
+
+	// Numeric dates without a language are month first, as on the English store pages
+	got, err = parseFlexibleDate("10/09/2019", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.October, got.Month())
+
+	got, err = parseFlexibleDate("10/09/2019", "de")
+	require.NoError(t, err)
+	assert.Equal(t, time.September, got.Month())
+}
@@ -0,0 +1,17 @@
+package store
+
+import "context"
+
+// DeveloperQuery holds the parameters of a developer's app list.
+type DeveloperQuery struct {
+	// ID is the developer identifier of the store, as in App.DeveloperID
+	ID      string
+	Lang    string
+	Country string
+}
+
+// DeveloperLister is implemented by stores that can list the apps of a developer.
+// Apps may lack details the store's developer page does not show.
+type DeveloperLister interface {
+	DeveloperApps(ctx context.Context, q DeveloperQuery) ([]App, error)
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppStoreDeveloperApps(t *testing.T) {
+	serveAppStoreFixtures(t)
+
+	apps, err := AppStore{}.DeveloperApps(context.Background(), DeveloperQuery{ID: "1592213653"})
+	require.NoError(t, err)
+	require.Len(t, apps, 2, "the artist entry is skipped")
+	assert.Equal(t, "com.arise.katsini", apps[0].BundleID)
+	assert.Equal(t, "1592213655", apps[1].AppID)
+	assert.Equal(t, "1.0.0", apps[1].Version)
+	assert.Equal(t, "1592213653", apps[1].DeveloperID)
+
+	_, err = AppStore{}.DeveloperApps(context.Background(), DeveloperQuery{ID: "1"})
+	assert.ErrorIs(t, err, ErrAppNotFound)
+
+	_, err = AppStore{}.DeveloperApps(context.Background(), DeveloperQuery{ID: "Arise"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestPlayStoreDeveloperApps(t *testing.T) {
+	servePlayStoreFixtures(t)
+
+	apps, err := PlayStore{}.DeveloperApps(context.Background(), DeveloperQuery{ID: "Mediocre"})
+	require.NoError(t, err)
+	require.Len(t, apps, 2)
+	assert.Equal(t, "com.mediocre.dirac", apps[0].BundleID)
+	assert.Equal(t, "Smash Hit", apps[1].Title)
+	assert.Equal(t, "Mediocre", apps[1].DeveloperID)
+
+	_, err = PlayStore{}.DeveloperApps(context.Background(), DeveloperQuery{ID: "5700313618786177705"})
+	assert.ErrorIs(t, err, ErrAppNotFound)
+}
+
+func TestPlayStoreDeveloperID(t *testing.T) {
+	assert.Equal(t, "Mediocre", playStoreDeveloperID("https://play.google.com/store/apps/developer?id=Mediocre"))
+	assert.Equal(t, "Example Inc", playStoreDeveloperID("https://play.google.com/store/apps/developer?id=Example+Inc"))
+	assert.Equal(t, "5700313618786177705", playStoreDeveloperID("https://play.google.com/store/apps/dev?id=5700313618786177705"))
+	assert.Empty(t, playStoreDeveloperID(""))
+}
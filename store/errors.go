@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Lookup errors are wrapped with context; match them with errors.Is.
+var (
+	// ErrAppNotFound means the store has no app with the given identifier.
+	ErrAppNotFound = errors.New("app not found")
+	// ErrPageLoad means the store did not answer in time.
+	ErrPageLoad = errors.New("failed to load page")
+	// ErrBlocked means the store refused to answer, e.g. rate limiting or bot detection.
+	ErrBlocked = errors.New("blocked by store")
+	// ErrUpstream means the store failed to answer, e.g. with a 5xx status.
+	ErrUpstream = errors.New("store error")
+	// ErrInvalidInput means the query can never succeed, e.g. a malformed identifier.
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// Retryable reports whether a failed lookup may succeed when tried again.
+func Retryable(err error) bool {
+	return !errors.Is(err, ErrAppNotFound) && !errors.Is(err, ErrInvalidInput)
+}
+
+// statusError converts an unexpected HTTP status of a store API to an error.
+func statusError(status int) error {
+	switch {
+	case status == http.StatusNotFound:
+		return ErrAppNotFound
+	case status == http.StatusForbidden || status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: status %d", ErrBlocked, status)
+	default:
+		return fmt.Errorf("%w: status %d", ErrUpstream, status)
+	}
+}
+
+// scrapeError converts the error of a Chrome scrape of url to a lookup error.
+// A pooled tab is cancelled when the caller's context ends, so chromedp reports
+// context.Canceled even when the caller's deadline passed; that is a timeout.
+func scrapeError(ctx context.Context, err error, url string) error {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return ctx.Err()
+	case errors.Is(ctx.Err(), context.DeadlineExceeded), strings.Contains(err.Error(), "context deadline exceeded"):
+		return fmt.Errorf("%w: timeout while extracting data from %s", ErrPageLoad, url)
+	case errors.Is(err, ErrAppNotFound):
+		return ErrAppNotFound
+	case errors.Is(err, ErrBlocked):
+		return err
+	default:
+		return fmt.Errorf("failed to extract app data from %s: %w", url, err)
+	}
+}
+
+var (
+	// numericID matches App Store track IDs and AppGallery app IDs
+	numericID = regexp.MustCompile(`^[0-9]+$`)
+	// packageName matches Android package names such as com.example.app
+	packageName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)+$`)
+	// countryCode matches two letter country codes such as us
+	countryCode = regexp.MustCompile(`^[A-Za-z]{2}$`)
+)
+
+// validateID returns ErrInvalidInput unless id is empty or matches pattern.
+func validateID(name, id string, pattern *regexp.Regexp) error {
+	if id != "" && !pattern.MatchString(id) {
+		return fmt.Errorf("%w: malformed %s %q", ErrInvalidInput, name, id)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	assert.ErrorIs(t, statusError(http.StatusNotFound), ErrAppNotFound)
+	assert.ErrorIs(t, statusError(http.StatusTooManyRequests), ErrBlocked)
+	assert.ErrorIs(t, statusError(http.StatusForbidden), ErrBlocked)
+	assert.ErrorIs(t, statusError(http.StatusBadGateway), ErrUpstream)
+	assert.EqualError(t, statusError(http.StatusServiceUnavailable), "store error: status 503")
+}
+
+func TestScrapeError(t *testing.T) {
+	const url = "https://appgallery.huawei.com/app/C1"
+
+	// The caller's deadline ends the pooled tab, which chromedp reports as cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	err := scrapeError(ctx, context.Canceled, url)
+	assert.ErrorIs(t, err, ErrPageLoad)
+	assert.NotErrorIs(t, err, context.Canceled)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, scrapeError(ctx, context.Canceled, url), context.Canceled)
+
+	assert.ErrorIs(t, scrapeError(context.Background(), context.DeadlineExceeded, url), ErrPageLoad)
+	assert.ErrorIs(t, scrapeError(context.Background(), fmt.Errorf("%w: captcha", ErrBlocked), url), ErrBlocked)
+	assert.Equal(t, ErrAppNotFound, scrapeError(context.Background(), ErrAppNotFound, url))
+	assert.EqualError(t, scrapeError(context.Background(), errors.New("node not found"), url),
+		"failed to extract app data from "+url+": node not found")
+}
+
+func TestValidateID(t *testing.T) {
+	assert.NoError(t, validateID("appId", "", numericID))
+	assert.NoError(t, validateID("appId", "1592213654", numericID))
+	assert.ErrorIs(t, validateID("appId", "invalid", numericID), ErrInvalidInput)
+	assert.ErrorIs(t, validateID("appId", "C100", numericID), ErrInvalidInput)
+
+	assert.NoError(t, validateID("bundleId", "com.mediocre.dirac", packageName))
+	assert.NoError(t, validateID("bundleId", "com.example_app.v2", packageName))
+	assert.ErrorIs(t, validateID("bundleId", "invalid", packageName), ErrInvalidInput)
+	assert.ErrorIs(t, validateID("bundleId", "com.example&hl=de", packageName), ErrInvalidInput)
+}
+
+func TestRetryable(t *testing.T) {
+	assert.False(t, Retryable(ErrAppNotFound))
+	assert.False(t, Retryable(validateID("appId", "x", numericID)))
+	assert.True(t, Retryable(statusError(http.StatusBadGateway)))
+	assert.True(t, Retryable(errors.New("failed to extract app data")))
+}
+
+func TestRetryOperationSkipsInvalidInput(t *testing.T) {
+	attempts := 0
+	_, err := retryOperation(context.Background(), func(context.Context) (App, error) {
+		attempts++
+		return App{}, ErrInvalidInput
+	}, 3, "test")
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	assert.Equal(t, 1, attempts)
+}
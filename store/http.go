@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// fetchJSON decodes the JSON response of a store API into v, classifying error
+// statuses and the HTML pages stores answer throttled clients with.
+func fetchJSON(ctx context.Context, url string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get %s: %w", ErrPageLoad, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read %s: %w", ErrPageLoad, url, err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+			return fmt.Errorf("%w: html page instead of json", ErrBlocked)
+		}
+		return fmt.Errorf("%w: failed to decode %s: %w", ErrUpstream, url, err)
+	}
+	return nil
+}
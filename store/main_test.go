@@ -0,0 +1,11 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/arisecode/katsini/internal/chrometest"
+)
+
+func TestMain(m *testing.M) {
+	chrometest.Main(m)
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// PlayMode selects how Google Play pages are fetched.
+type PlayMode string
+
+const (
+	// PlayModeAuto fetches over plain HTTP and falls back to Chrome.
+	PlayModeAuto PlayMode = "auto"
+	// PlayModeHTTP only fetches over plain HTTP.
+	PlayModeHTTP PlayMode = "http"
+	// PlayModeChrome only scrapes the page in Chrome.
+	PlayModeChrome PlayMode = "chrome"
+)
+
+// PlayModeFromEnv reads the mode from PLAYSTORE_MODE, defaulting to PlayModeAuto.
+func PlayModeFromEnv() PlayMode {
+	switch mode := PlayMode(strings.ToLower(os.Getenv("PLAYSTORE_MODE"))); mode {
+	case PlayModeHTTP, PlayModeChrome:
+		return mode
+	case "", PlayModeAuto:
+		return PlayModeAuto
+	default:
+		log.Printf("Invalid PLAYSTORE_MODE %q, using %s", mode, PlayModeAuto)
+		return PlayModeAuto
+	}
+}
+
+// PlayStore exposes GooglePlayStore through the Store interface.
+type PlayStore struct {
+	// Mode selects how pages are fetched; the zero value is PlayModeAuto.
+	Mode PlayMode
+}
+
+func (PlayStore) Name() string { return "playstore" }
+
+func (PlayStore) Title() string { return "Google Play Store" }
+
+func (PlayStore) Identifiers() []Identifier { return []Identifier{IdentifierBundleID} }
+
+func (PlayStore) Options() []string { return []string{"lang", "country"} }
+
+func (p PlayStore) Lookup(ctx context.Context, q Query) (App, error) {
+	switch p.Mode {
+	case PlayModeHTTP:
+		return GooglePlayStoreHTTP(ctx, q.BundleID, q.Lang, q.Country)
+	case PlayModeChrome:
+		return GooglePlayStoreChrome(ctx, q.BundleID, q.Lang, q.Country)
+	default:
+		return GooglePlayStore(ctx, q.BundleID, q.Lang, q.Country)
+	}
+}
+
+// GooglePlayStore fetches an app from the Google Play Store by its package
+// name over plain HTTP, falling back to Chrome when the page cannot be decoded.
+func GooglePlayStore(ctx context.Context, bundleID, lang, country string) (App, error) {
+	app, err := GooglePlayStoreHTTP(ctx, bundleID, lang, country)
+	if err == nil || !Retryable(err) || ctx.Err() != nil {
+		return app, err
+	}
+
+	log.Printf("Falling back to Chrome for Google Play Store bundleID %s: %v", bundleID, err)
+	return GooglePlayStoreChrome(ctx, bundleID, lang, country)
+}
+
+// GooglePlayStoreChrome scrapes an app from the Google Play Store in Chrome.
+func GooglePlayStoreChrome(ctx context.Context, bundleID, lang, country string) (App, error) {
+	if err := validateID("bundleId", bundleID, packageName); err != nil {
+		return App{}, err
+	}
+
+	app := App{
+		BundleID: bundleID,
+	}
+
+	if lang == "" {
+		lang = "en"
+	}
+
+	if country == "" {
+		country = "us"
+	}
+
+	log.Printf("Fetching Google Play Store app data for bundleID: %s, lang: %s, country: %s", bundleID, lang, country)
+	app.URL = fmt.Sprintf("https://play.google.com/store/apps/details?id=%s&hl=%s&gl=%s", app.BundleID, lang, country)
+
+	// Open an isolated incognito tab on a warm browser from the shared pool
+	taskCtx, release, err := DefaultBrowserPool().Acquire(ctx)
+	if err != nil {
+		return App{}, fmt.Errorf("failed to acquire browser tab: %w", err)
+	}
+
+	chromedp.ListenTarget(taskCtx, DisableFetchExceptScripts(taskCtx, append(commonResourceTypesToBlock, network.ResourceTypeStylesheet)))
+
+	// set a timeout to avoid long waits
+	timeoutCtx, cancel := context.WithTimeout(taskCtx, DefaultTimeout)
+	defer cancel()
+
+	var page pageInfo
+	var html string
+
+	// The details page embeds the same data blocks as over plain HTTP, so
+	// nothing depends on the labels or date format of the page language
+	resp, err := chromedp.RunResponse(timeoutCtx, fetch.Enable(), chromedp.Navigate(app.URL))
+	if err == nil && resp != nil && resp.Status == http.StatusNotFound {
+		err = ErrAppNotFound
+	}
+	if err == nil {
+		err = chromedp.Run(timeoutCtx,
+			chromedp.Evaluate(pageInfoScript, &page),
+			chromedp.OuterHTML("html", &html, chromedp.ByQuery),
+			// Recognise captcha, unusual traffic and consent pages before looking for the app
+			chromedp.ActionFunc(func(_ context.Context) error {
+				return playStoreBlockError(page, html)
+			}),
+		)
+	}
+	release(err)
+	if err != nil {
+		return App{}, scrapeError(ctx, err, app.URL)
+	}
+
+	return decodePlayStoreApp(app, html)
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+)
+
+const (
+	// playChartRPC is the batchexecute procedure serving the app lists of the Play web app
+	playChartRPC = "vyAe2"
+	// playChartSize is the number of apps requested when no limit is given
+	playChartSize = 100
+	// playOverallCategory is the category of the overall charts
+	playOverallCategory = "APPLICATION"
+)
+
+// playCollections maps chart names to the collections of the list procedure.
+var playCollections = map[string]string{
+	ChartTopFree:     "TOP_FREE",
+	ChartTopPaid:     "TOP_PAID",
+	ChartTopGrossing: "GROSSING",
+}
+
+// playChartFields are the app fields requested from the list procedure.
+var playChartFields = []int{
+	64, 1, 195, 71, 8, 72, 9, 10, 11, 139, 12, 16, 145, 148, 150, 151, 152,
+	27, 30, 31, 96, 32, 34, 163, 100, 165, 104, 169, 108, 110, 113, 55, 56, 57, 122,
+}
+
+// playCategory matches Google Play category ids such as "GAME_ACTION".
+var playCategory = regexp.MustCompile(`^[A-Z][A-Z_]*$`)
+
+// Chart fetches a top chart through the list procedure of the batchexecute
+// endpoint, as Play no longer serves chart pages. Categories are Play
+// category ids such as "GAME_ACTION".
+func (PlayStore) Chart(ctx context.Context, q ChartQuery) ([]App, error) {
+	collection, ok := playCollections[q.Chart]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown chart %q", ErrInvalidInput, q.Chart)
+	}
+	if err := validateID("category", q.Category, playCategory); err != nil {
+		return nil, err
+	}
+
+	lang := cmp.Or(q.Lang, "en")
+	country := cmp.Or(q.Country, "us")
+	size := playChartSize
+	if q.Limit > 0 {
+		size = q.Limit
+	}
+
+	request, err := json.Marshal([]any{
+		[]any{nil, []any{[]any{8, []any{20, size}}, true, nil, playChartFields}},
+		[]any{collection, 7, cmp.Or(q.Category, playOverallCategory)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Fetching Google Play Store %s chart, category: %q", q.Chart, q.Category)
+	data, err := playBatchExecute(ctx, playChartRPC, string(request), lang, country)
+	if err != nil {
+		return nil, err
+	}
+
+	// Play answers unknown categories with an empty payload rather than an error status
+	entries, _ := jsonAt(data, 0, 1, 0, 28, 0).([]any)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no %s chart for category %q", ErrAppNotFound, q.Chart, q.Category)
+	}
+
+	apps := make([]App, 0, len(entries))
+	for _, entry := range entries {
+		app, ok := jsonAt(entry, 0).([]any)
+		bundleID := jsonString(app, 0, 0)
+		if !ok || !packageName.MatchString(bundleID) {
+			continue
+		}
+		apps = append(apps, playStoreListedApp(app, bundleID, lang, country))
+		if q.Limit > 0 && len(apps) == q.Limit {
+			break
+		}
+	}
+	return apps, nil
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+)
+
+// DeveloperApps lists the apps on a Google Play developer page. Developers
+// with a custom page have numeric ids, all others are identified by name.
+func (PlayStore) DeveloperApps(ctx context.Context, q DeveloperQuery) ([]App, error) {
+	if q.ID == "" {
+		return nil, fmt.Errorf("%w: missing developerId", ErrInvalidInput)
+	}
+
+	lang := cmp.Or(q.Lang, "en")
+	country := cmp.Or(q.Country, "us")
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	path := "/store/apps/developer"
+	if numericID.MatchString(q.ID) {
+		path = "/store/apps/dev"
+	}
+	params := url.Values{"id": {q.ID}, "hl": {lang}, "gl": {country}}
+
+	log.Printf("Fetching Google Play Store apps of developer: %s", q.ID)
+	page, err := fetchPlayStorePage(ctx, playStoreBaseURL+path+"?"+params.Encode(), lang)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := initDataBlocks(page)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := playStoreListedApps(blocks, lang, country, 0)
+	for i := range apps {
+		apps[i].DeveloperID = q.ID
+	}
+	return apps, nil
+}
+
+// playStoreDeveloperID returns the developer id of a developer page link such
+// as "https://play.google.com/store/apps/dev?id=5700313618786177705".
+func playStoreDeveloperID(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("id")
+}
@@ -0,0 +1,230 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// playStoreBaseURL is the Google Play origin, replaced by tests.
+var playStoreBaseURL = "https://play.google.com"
+
+// playStoreUserAgent is sent with plain HTTP requests; Play serves a reduced page to unknown clients.
+const playStoreUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// initDataCallback finds the start of the data blocks embedded in Play pages,
+// e.g. AF_initDataCallback({key: 'ds:5', hash: '7', data:[...], sideChannel: {}});
+var initDataCallback = regexp.MustCompile(`AF_initDataCallback\(\{key:\s*'(ds:\d+)'[^\[]*?data:`)
+
+// lineBreak matches the <br> tags separating lines of Play's text fields
+var lineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// GooglePlayStoreHTTP fetches an app from the Google Play Store without a
+// browser, decoding the data blocks embedded in the details page.
+func GooglePlayStoreHTTP(ctx context.Context, bundleID, lang, country string) (App, error) {
+	if err := validateID("bundleId", bundleID, packageName); err != nil {
+		return App{}, err
+	}
+
+	if lang == "" {
+		lang = "en"
+	}
+
+	if country == "" {
+		country = "us"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	log.Printf("Fetching Google Play Store app data over HTTP for bundleID: %s, lang: %s, country: %s", bundleID, lang, country)
+	app := App{
+		BundleID: bundleID,
+		URL:      fmt.Sprintf("https://play.google.com/store/apps/details?id=%s&hl=%s&gl=%s", bundleID, lang, country),
+	}
+
+	page, err := fetchPlayStorePage(ctx, fmt.Sprintf("%s/store/apps/details?id=%s&hl=%s&gl=%s", playStoreBaseURL, bundleID, lang, country), lang)
+	if err != nil {
+		return App{}, err
+	}
+
+	return decodePlayStoreApp(app, page)
+}
+
+// decodePlayStoreApp fills app from the data blocks of a details page. The
+// data is the same in every language, so no label or date text is parsed.
+func decodePlayStoreApp(app App, page string) (App, error) {
+	details, err := playStoreDetails(page)
+	if err != nil {
+		return App{}, err
+	}
+
+	app.Title = jsonString(details, 0, 0)
+	app.Developer = jsonString(details, 68, 0)
+	app.DeveloperID = playStoreDeveloperID(jsonString(details, 68, 1, 4, 2))
+	app.Version = jsonString(details, 140, 0, 0, 0)
+	if app.Version == "" {
+		// Apps with device specific builds publish no single version
+		app.Version = "Varies with device"
+	}
+
+	updated, ok := jsonNumber(details, 145, 0, 1, 0)
+	if !ok {
+		return App{}, fmt.Errorf("failed to extract app data: missing update date")
+	}
+	app.Updated = time.Unix(int64(updated), 0).UTC().Format("02-01-2006")
+
+	app.Installs = jsonString(details, 13, 0)
+	app.Icon = jsonString(details, 95, 0, 3, 2)
+	app.ContentRating = jsonString(details, 9, 0)
+	app.Category = jsonString(details, 79, 0, 0, 0)
+	app.ReleaseNotes = playStoreText(jsonString(details, 144, 1, 1))
+	app.MinOSVersion = jsonString(details, 140, 1, 1, 0, 0, 1)
+	app.Rating, _ = jsonNumber(details, 51, 0, 1)
+	if count, ok := jsonNumber(details, 51, 2, 1); ok {
+		app.RatingCount = int64(count)
+	}
+	app.Histogram = playStoreHistogram(jsonAt(details, 51, 1))
+	// Prices are given in micros of the currency; free apps carry a price of 0
+	if micros, ok := jsonNumber(details, 57, 0, 0, 0, 0, 1, 0, 0); ok && micros > 0 {
+		app.Price = micros / 1e6
+		app.Currency = jsonString(details, 57, 0, 0, 0, 0, 1, 0, 1)
+	}
+
+	if app.Title == "" {
+		return App{}, fmt.Errorf("failed to extract app data: missing app title")
+	}
+	return app, nil
+}
+
+// playStoreHistogram converts the rating histogram of a details page, which
+// lists [stars, count] pairs at the index of their star count.
+func playStoreHistogram(v any) []int64 {
+	histogram := make([]int64, 0, MaxRating)
+	for stars := 1; stars <= MaxRating; stars++ {
+		count, ok := jsonNumber(v, stars, 1)
+		if !ok {
+			return nil
+		}
+		histogram = append(histogram, int64(count))
+	}
+	return histogram
+}
+
+// playStoreText converts the HTML snippets Play uses for descriptions and
+// release notes to plain text.
+func playStoreText(s string) string {
+	s = lineBreak.ReplaceAllString(s, "\n")
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+// fetchPlayStorePage downloads a Play page, classifying error statuses and interstitials.
+func fetchPlayStorePage(ctx context.Context, url, lang string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", playStoreUserAgent)
+	req.Header.Set("Accept-Language", lang)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", fmt.Errorf("%w: %w", ErrPageLoad, err)
+		}
+		return "", fmt.Errorf("failed to get app: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read page: %w", err)
+	}
+
+	page := string(body)
+	if err := playStoreBlockError(pageInfo{
+		URL:     resp.Request.URL.String(),
+		Text:    page,
+		Captcha: strings.Contains(page, "g-recaptcha"),
+	}, page); err != nil {
+		return "", err
+	}
+	return page, nil
+}
+
+// playStoreDetails returns the app details embedded in a details page. The
+// block holding them moves between ds keys over time, so every block is
+// checked for the title and version paths.
+func playStoreDetails(page string) ([]any, error) {
+	blocks, err := initDataBlocks(page)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		details, ok := jsonAt(block, 1, 2).([]any)
+		if !ok {
+			continue
+		}
+		if jsonString(details, 0, 0) != "" && jsonAt(details, 140) != nil {
+			return details, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to extract app data: no app details in %d data blocks", len(blocks))
+}
+
+// initDataBlocks decodes the data of every AF_initDataCallback block of a page, by key.
+func initDataBlocks(page string) (map[string]any, error) {
+	blocks := make(map[string]any)
+	for _, match := range initDataCallback.FindAllStringSubmatchIndex(page, -1) {
+		key := page[match[2]:match[3]]
+
+		// The data array is valid JSON; the decoder stops at its end
+		var data any
+		if err := json.NewDecoder(strings.NewReader(page[match[1]:])).Decode(&data); err != nil {
+			return nil, fmt.Errorf("failed to decode data block %s: %w", key, err)
+		}
+		blocks[key] = data
+	}
+
+	if len(blocks) == 0 {
+		return nil, errors.New("failed to extract app data: page has no data blocks")
+	}
+	return blocks, nil
+}
+
+// jsonAt walks nested JSON arrays, returning nil when the path does not exist.
+func jsonAt(v any, path ...int) any {
+	for _, i := range path {
+		arr, ok := v.([]any)
+		if !ok || i < 0 || i >= len(arr) {
+			return nil
+		}
+		v = arr[i]
+	}
+	return v
+}
+
+// jsonString returns the string at path, or "" when there is none.
+func jsonString(v any, path ...int) string {
+	s, _ := jsonAt(v, path...).(string)
+	return s
+}
+
+// jsonNumber returns the number at path.
+func jsonNumber(v any, path ...int) (float64, bool) {
+	n, ok := jsonAt(v, path...).(float64)
+	return n, ok
+}
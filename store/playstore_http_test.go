@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// servePlayStoreFixtures serves the saved pages and responses in testdata/playstore
+// in place of Google Play for the duration of the test
+func servePlayStoreFixtures(t *testing.T) {
+	t.Helper()
+
+	fixtures := map[string]string{
+		"com.mediocre.dirac":  "com.mediocre.dirac.html",
+		"com.example.varies":  "varies.html",
+		"com.example.consent": "consent.html",
+		"search":              "search.html",
+		"developer:Mediocre":  "developer.html",
+		"reviews":             "reviews.txt",
+		"reviews:page2token":  "reviews-2.txt",
+		"reviews:none":        "reviews-none.txt",
+		"chart":               "chart.txt",
+		"chart:none":          "chart-none.txt",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		switch r.URL.Path {
+		case "/store/search":
+			id = "search"
+		case "/store/apps/developer", "/store/apps/dev":
+			id = "developer:" + id
+		case "/_/PlayStoreUi/data/batchexecute":
+			request := r.FormValue("f.req")
+			switch {
+			case r.URL.Query().Get("rpcids") == playChartRPC && strings.Contains(request, `\"TOP_FREE\",7,\"GAME\"`):
+				id = "chart"
+			case r.URL.Query().Get("rpcids") == playChartRPC:
+				id = "chart:none"
+			case !strings.Contains(request, "com.mediocre.dirac"):
+				id = "reviews:none"
+			case strings.Contains(request, "page2token"):
+				id = "reviews:page2token"
+			default:
+				id = "reviews"
+			}
+		}
+		if id == "com.example.limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		fixture, ok := fixtures[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		page, err := os.ReadFile(filepath.Join("testdata", "playstore", fixture))
+		if err != nil {
+			t.Errorf("Failed to read fixture: %v", err)
+		}
+		_, _ = w.Write(page)
+	}))
+	t.Cleanup(srv.Close)
+
+	original := playStoreBaseURL
+	playStoreBaseURL = srv.URL
+	t.Cleanup(func() { playStoreBaseURL = original })
+}
+
+func TestGooglePlayStoreHTTP(t *testing.T) {
+	servePlayStoreFixtures(t)
+
+	app, err := GooglePlayStoreHTTP(context.Background(), "com.mediocre.dirac", "en", "us")
+	require.NoError(t, err)
+	assert.Equal(t, App{
+		BundleID:  "com.mediocre.dirac",
+		URL:       "https://play.google.com/store/apps/details?id=com.mediocre.dirac&hl=en&gl=us",
+		Title:     "Beyondium",
+		Version:   "1.1.5",
+		Updated:   "31-10-2019",
+		Developer: "Mediocre",
+
+		DeveloperID:   "Mediocre",
+		Installs:      "100,000+",
+		Currency:      "USD",
+		Icon:          "https://play-lh.googleusercontent.com/beyondium-icon",
+		ContentRating: "Everyone",
+		Category:      "Arcade",
+		ReleaseNotes:  "Fixed a crash on start.\nImproved controls & menus.",
+		MinOSVersion:  "5.0",
+		Rating:        4.2857141,
+		RatingCount:   2345,
+		Histogram:     []int64{210, 95, 240, 500, 1300},
+		Price:         2.99,
+	}, app)
+
+	app, err = GooglePlayStoreHTTP(context.Background(), "com.example.varies", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Varies with device", app.Version)
+	assert.Equal(t, "14-11-2023", app.Updated)
+	assert.Equal(t, "Varies with device", app.MinOSVersion)
+	assert.Zero(t, app.Price)
+	assert.Empty(t, app.Currency)
+}
+
+func TestGooglePlayStoreHTTPErrors(t *testing.T) {
+	servePlayStoreFixtures(t)
+
+	_, err := GooglePlayStoreHTTP(context.Background(), "com.example.missing", "en", "us")
+	assert.ErrorIs(t, err, ErrAppNotFound)
+
+	_, err = GooglePlayStoreHTTP(context.Background(), "com.example.limited", "en", "us")
+	assert.ErrorIs(t, err, ErrBlocked)
+
+	_, err = GooglePlayStoreHTTP(context.Background(), "com.example.consent", "en", "us")
+	assert.ErrorIs(t, err, ErrBlocked)
+
+	_, err = GooglePlayStoreHTTP(context.Background(), "invalid", "en", "us")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestPlayStoreDetails(t *testing.T) {
+	_, err := playStoreDetails(`<html><script>AF_initDataCallback({key: 'ds:0', hash: '1', data:[1,[2]], sideChannel: {}});</script></html>`)
+	assert.EqualError(t, err, "failed to extract app data: no app details in 1 data blocks")
+
+	_, err = playStoreDetails(`<html></html>`)
+	assert.EqualError(t, err, "failed to extract app data: page has no data blocks")
+}
+
+func TestPlayModeFromEnv(t *testing.T) {
+	t.Setenv("PLAYSTORE_MODE", "")
+	assert.Equal(t, PlayModeAuto, PlayModeFromEnv())
+
+	t.Setenv("PLAYSTORE_MODE", "HTTP")
+	assert.Equal(t, PlayModeHTTP, PlayModeFromEnv())
+
+	t.Setenv("PLAYSTORE_MODE", "selenium")
+	assert.Equal(t, PlayModeAuto, PlayModeFromEnv())
+}
+
+func TestJSONAt(t *testing.T) {
+	v := []any{nil, []any{"a", []any{1.0}}}
+	assert.Equal(t, "a", jsonString(v, 1, 0))
+	assert.Empty(t, jsonString(v, 1, 1))
+	assert.Nil(t, jsonAt(v, 0, 1))
+	assert.Nil(t, jsonAt(v, 5))
+
+	n, ok := jsonNumber(v, 1, 1, 0)
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0, n, 0)
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// playReviewsRPC is the batchexecute procedure serving the reviews of an app
+	playReviewsRPC = "UsvDTd"
+	// playReviewPageSize is the number of reviews requested per page
+	playReviewPageSize = 40
+)
+
+// playReviewSorts maps review sort orders to the sort values of the reviews procedure.
+var playReviewSorts = map[string]int{
+	SortHelpful: 1,
+	SortNewest:  2,
+}
+
+// Reviews fetches a page of reviews through the batchexecute endpoint of the
+// Play web app. Play pages by an opaque cursor, carried in the page token.
+func (PlayStore) Reviews(ctx context.Context, q ReviewQuery) (ReviewPage, error) {
+	if q.ID == "" {
+		return ReviewPage{}, fmt.Errorf("%w: missing bundleId", ErrInvalidInput)
+	}
+	if err := validateID("bundleId", q.ID, packageName); err != nil {
+		return ReviewPage{}, err
+	}
+	sort, ok := playReviewSorts[cmp.Or(q.Sort, SortNewest)]
+	if !ok {
+		return ReviewPage{}, fmt.Errorf("%w: unknown sort %q", ErrInvalidInput, q.Sort)
+	}
+
+	var cursor any
+	if q.Page != "" {
+		state, err := decodePageToken(q.Page)
+		if err != nil {
+			return ReviewPage{}, err
+		}
+		cursor = state
+	}
+
+	request, err := json.Marshal([]any{
+		nil, nil,
+		[]any{2, sort, []any{playReviewPageSize, nil, cursor}, nil, []any{}},
+		[]any{q.ID, 7},
+	})
+	if err != nil {
+		return ReviewPage{}, err
+	}
+
+	log.Printf("Fetching Google Play Store reviews for bundle ID: %s", q.ID)
+	data, err := playBatchExecute(ctx, playReviewsRPC, string(request), cmp.Or(q.Lang, "en"), cmp.Or(q.Country, "us"))
+	if err != nil {
+		return ReviewPage{}, err
+	}
+	// Play answers unknown apps with an empty payload rather than an error status
+	if data == nil {
+		return ReviewPage{}, fmt.Errorf("%w: no reviews for %s", ErrAppNotFound, q.ID)
+	}
+
+	entries, _ := jsonAt(data, 0).([]any)
+	result := ReviewPage{Reviews: make([]Review, 0, len(entries))}
+	for _, entry := range entries {
+		if review, ok := playReview(entry); ok {
+			result.Reviews = append(result.Reviews, review)
+		}
+	}
+	if next := jsonString(data, 1, 1); next != "" && len(entries) > 0 {
+		result.NextPage = encodePageToken(next)
+	}
+	return result, nil
+}
+
+// playReview converts a review entry of the reviews procedure.
+func playReview(entry any) (Review, bool) {
+	id := jsonString(entry, 0)
+	rating, ok := jsonNumber(entry, 2)
+	if id == "" || !ok {
+		return Review{}, false
+	}
+
+	review := Review{
+		ID:      id,
+		Author:  jsonString(entry, 1, 0),
+		Body:    jsonString(entry, 4),
+		Version: jsonString(entry, 10),
+		Rating:  int(rating),
+	}
+	if seconds, ok := jsonNumber(entry, 5, 0); ok {
+		review.Date = time.Unix(int64(seconds), 0).UTC()
+	}
+	if reply := jsonString(entry, 7, 1); reply != "" {
+		review.Reply = &ReviewReply{Body: reply}
+		if seconds, ok := jsonNumber(entry, 7, 2, 0); ok {
+			review.Reply.Date = time.Unix(int64(seconds), 0).UTC()
+		}
+	}
+	return review, true
+}
+
+// playBatchExecute calls a procedure of the batchexecute endpoint of the Play
+// web app, returning its decoded payload, or nil when the payload is empty.
+func playBatchExecute(ctx context.Context, rpc, request, lang, country string) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	envelope, err := json.Marshal([][][]any{{{rpc, request, nil, "generic"}}})
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{"rpcids": {rpc}, "hl": {lang}, "gl": {country}}
+	form := url.Values{"f.req": {string(envelope)}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		playStoreBaseURL+"/_/PlayStoreUi/data/batchexecute?"+params.Encode(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+	req.Header.Set("User-Agent", playStoreUserAgent)
+	req.Header.Set("Accept-Language", lang)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", ErrPageLoad, err)
+		}
+		return nil, fmt.Errorf("failed to call %s: %w", rpc, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", rpc, err)
+	}
+	return decodeBatchExecute(body, rpc)
+}
+
+// decodeBatchExecute extracts the payload of a procedure from a batchexecute
+// response. Responses open with an anti-JSON-hijacking prefix, and carry the
+// payload as a JSON string inside a "wrb.fr" envelope.
+func decodeBatchExecute(body []byte, rpc string) (any, error) {
+	body = bytes.TrimPrefix(bytes.TrimSpace(body), []byte(")]}'"))
+
+	var envelopes [][]any
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&envelopes); err != nil {
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+			return nil, fmt.Errorf("%w: html page instead of json", ErrBlocked)
+		}
+		return nil, fmt.Errorf("%w: failed to decode %s response: %w", ErrUpstream, rpc, err)
+	}
+
+	for _, envelope := range envelopes {
+		if jsonString(envelope, 0) != "wrb.fr" || jsonString(envelope, 1) != rpc {
+			continue
+		}
+		payload := jsonString(envelope, 2)
+		if payload == "" {
+			return nil, nil
+		}
+		var data any
+		if err := json.Unmarshal([]byte(payload), &data); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode %s payload: %w", ErrUpstream, rpc, err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: no %s payload in response", ErrUpstream, rpc)
+}
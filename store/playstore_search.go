@@ -0,0 +1,106 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Search finds apps on the Google Play search results page.
+func (PlayStore) Search(ctx context.Context, q SearchQuery) ([]App, error) {
+	lang := cmp.Or(q.Lang, "en")
+	country := cmp.Or(q.Country, "us")
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	params := url.Values{"q": {q.Term}, "c": {"apps"}, "hl": {lang}, "gl": {country}}
+	log.Printf("Searching Google Play Store for term: %s", q.Term)
+	page, err := fetchPlayStorePage(ctx, playStoreBaseURL+"/store/search?"+params.Encode(), lang)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := initDataBlocks(page)
+	if err != nil {
+		return nil, err
+	}
+	return playStoreListedApps(blocks, lang, country, q.Limit), nil
+}
+
+// playStoreListedApps collects the apps listed in the data blocks of a search
+// or list page, in page order and without duplicates. Listed apps are found by
+// their shape, a package name at [0][0] and a title at [3], since the
+// position of the list moves between page layouts. A limit of 0 returns every app.
+func playStoreListedApps(blocks map[string]any, lang, country string, limit int) []App {
+	keys := make([]string, 0, len(blocks))
+	for key := range blocks {
+		keys = append(keys, key)
+	}
+	// Block keys are numbered in page order
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Compare(blockNumber(a), blockNumber(b))
+	})
+
+	apps := []App{}
+	seen := make(map[string]bool)
+	var walk func(v any) bool
+	walk = func(v any) bool {
+		arr, ok := v.([]any)
+		if !ok {
+			return true
+		}
+
+		bundleID := jsonString(arr, 0, 0)
+		title := jsonString(arr, 3)
+		if title != "" && packageName.MatchString(bundleID) {
+			if !seen[bundleID] {
+				seen[bundleID] = true
+				apps = append(apps, playStoreListedApp(arr, bundleID, lang, country))
+			}
+			return limit == 0 || len(apps) < limit
+		}
+
+		for _, item := range arr {
+			if !walk(item) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, key := range keys {
+		if !walk(blocks[key]) {
+			break
+		}
+	}
+	return apps
+}
+
+// playStoreListedApp converts an app entry of a search or list page.
+func playStoreListedApp(entry []any, bundleID, lang, country string) App {
+	app := App{
+		BundleID:  bundleID,
+		URL:       fmt.Sprintf("https://play.google.com/store/apps/details?id=%s&hl=%s&gl=%s", bundleID, lang, country),
+		Title:     jsonString(entry, 3),
+		Developer: jsonString(entry, 14),
+		Icon:      jsonString(entry, 1, 3, 2),
+	}
+	app.Rating, _ = jsonNumber(entry, 4, 1)
+	if micros, ok := jsonNumber(entry, 8, 1, 0, 0); ok && micros > 0 {
+		app.Price = micros / 1e6
+		app.Currency = jsonString(entry, 8, 1, 0, 1)
+	}
+	return app
+}
+
+// blockNumber returns the number of a data block key such as "ds:4".
+func blockNumber(key string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(key, "ds:"))
+	return n
+}
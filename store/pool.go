@@ -0,0 +1,343 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+)
+
+// healthCheckTimeout bounds the probe run on a browser after a failed tab
+const healthCheckTimeout = 2 * time.Second
+
+// ErrPoolClosed is returned when a tab is requested from a closed browser pool.
+var ErrPoolClosed = errors.New("browser pool closed")
+
+// PoolConfig configures a BrowserPool.
+type PoolConfig struct {
+	// Size is the number of warm browsers kept running.
+	Size int
+	// MaxUses is the number of tabs a browser serves before it is recycled.
+	MaxUses int
+	// MaxTabs bounds the number of concurrently open tabs; further requests queue.
+	MaxTabs int
+}
+
+const (
+	// DefaultPoolSize is the number of warm browsers of an unconfigured pool
+	DefaultPoolSize = 2
+	// DefaultPoolMaxUses is the number of tabs a browser of an unconfigured pool serves
+	DefaultPoolMaxUses = 100
+)
+
+// PoolStats is a snapshot of a BrowserPool.
+type PoolStats struct {
+	Size     int    `json:"size"`     // Configured number of warm browsers
+	MaxTabs  int    `json:"maxTabs"`  // Configured maximum of concurrent tabs
+	Browsers int    `json:"browsers"` // Browsers currently running
+	Active   int    `json:"active"`   // Tabs currently in use
+	Waiting  int    `json:"waiting"`  // Requests queued for a tab
+	Acquired uint64 `json:"acquired"` // Tabs handed out since start
+	Launched uint64 `json:"launched"` // Browsers started since start
+	Recycled uint64 `json:"recycled"` // Browsers retired after reaching MaxUses
+	Crashed  uint64 `json:"crashed"`  // Browsers retired because they died
+}
+
+// pooledBrowser is a running browser and its bookkeeping.
+type pooledBrowser struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	uses    int
+	active  int
+	retired bool
+}
+
+// BrowserPool keeps warm Chrome instances and hands out isolated incognito
+// tabs, so a lookup does not pay for a browser start.
+type BrowserPool struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	tabs     chan struct{}
+	launch   func(context.Context) (context.Context, context.CancelFunc, error)
+	browsers []*pooledBrowser
+	cfg      PoolConfig
+	stats    PoolStats
+	starting int
+	mu       sync.Mutex
+	closed   bool
+}
+
+var (
+	defaultPool       *BrowserPool
+	defaultPoolOnce   sync.Once
+	defaultPoolConfig = PoolConfig{Size: DefaultPoolSize, MaxUses: DefaultPoolMaxUses, MaxTabs: 2 * DefaultPoolSize}
+)
+
+// ConfigureDefaultBrowserPool sets the configuration of the pool returned by
+// DefaultBrowserPool. It must be called before the pool is first used.
+func ConfigureDefaultBrowserPool(cfg PoolConfig) {
+	defaultPoolConfig = cfg
+}
+
+// DefaultBrowserPool returns the pool used by GooglePlayStore and HuaweiAppGallery.
+// It is created on first use.
+func DefaultBrowserPool() *BrowserPool {
+	defaultPoolOnce.Do(func() {
+		defaultPool = NewBrowserPool(defaultPoolConfig)
+	})
+	return defaultPool
+}
+
+// NewBrowserPool creates a pool; browsers are started by Warm or on first use.
+func NewBrowserPool(cfg PoolConfig) *BrowserPool {
+	if cfg.Size < 1 {
+		cfg.Size = 1
+	}
+	if cfg.MaxTabs < 1 {
+		cfg.MaxTabs = cfg.Size
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BrowserPool{
+		ctx:    ctx,
+		cancel: cancel,
+		tabs:   make(chan struct{}, cfg.MaxTabs),
+		launch: createBrowserContext,
+		cfg:    cfg,
+	}
+}
+
+// Warm starts browsers until the pool holds its configured size.
+func (p *BrowserPool) Warm() error {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return ErrPoolClosed
+		}
+		if p.running()+p.starting >= p.cfg.Size {
+			p.mu.Unlock()
+			return nil
+		}
+		p.starting++
+		p.mu.Unlock()
+
+		if _, err := p.start(); err != nil {
+			return err
+		}
+	}
+}
+
+// Acquire waits for a free slot and returns a new incognito tab bound to ctx.
+// The caller must call release with the error of its work once done with the tab.
+func (p *BrowserPool) Acquire(ctx context.Context) (tab context.Context, release func(error), err error) {
+	p.mu.Lock()
+	p.stats.Waiting++
+	p.mu.Unlock()
+
+	select {
+	case p.tabs <- struct{}{}:
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.stats.Waiting--
+		p.mu.Unlock()
+		return nil, nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	p.stats.Waiting--
+	p.mu.Unlock()
+
+	b, err := p.pick()
+	if err != nil {
+		<-p.tabs
+		return nil, nil, err
+	}
+
+	tab, cancel := chromedp.NewContext(b.ctx, chromedp.WithNewBrowserContext())
+	stop := context.AfterFunc(ctx, cancel)
+
+	var once sync.Once
+	release = func(err error) {
+		once.Do(func() {
+			stop()
+			cancel()
+			p.release(b, err)
+			<-p.tabs
+		})
+	}
+	return tab, release, nil
+}
+
+// Stats returns a snapshot of the pool.
+func (p *BrowserPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Size = p.cfg.Size
+	stats.MaxTabs = p.cfg.MaxTabs
+	stats.Browsers = p.running()
+	stats.Active = len(p.tabs)
+	return stats
+}
+
+// Close stops every browser; pending and future Acquire calls fail.
+func (p *BrowserPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for _, b := range p.browsers {
+		b.cancel()
+	}
+	p.browsers = nil
+	p.cancel()
+}
+
+// pick returns the least busy healthy browser, starting one if the pool is not full.
+func (p *BrowserPool) pick() (*pooledBrowser, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+	p.reap()
+
+	var best *pooledBrowser
+	for _, b := range p.browsers {
+		if !b.retired && (best == nil || b.active < best.active) {
+			best = b
+		}
+	}
+	if best != nil && (best.active == 0 || p.running()+p.starting >= p.cfg.Size) {
+		best.active++
+		p.stats.Acquired++
+		p.mu.Unlock()
+		return best, nil
+	}
+	p.starting++
+	p.mu.Unlock()
+
+	b, err := p.start()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b.active++
+	p.stats.Acquired++
+	return b, nil
+}
+
+// start launches a browser and adds it to the pool. Callers must have
+// incremented p.starting, which start decrements once done.
+func (p *BrowserPool) start() (*pooledBrowser, error) {
+	b, err := p.launchBrowser()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.starting--
+	if err != nil {
+		return nil, err
+	}
+	if p.closed {
+		b.cancel()
+		return nil, ErrPoolClosed
+	}
+	p.browsers = append(p.browsers, b)
+	p.stats.Launched++
+	return b, nil
+}
+
+// launchBrowser starts a browser process.
+func (p *BrowserPool) launchBrowser() (*pooledBrowser, error) {
+	ctx, cancel, err := p.launch(p.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create browser context: %w", err)
+	}
+
+	// Running without actions starts the browser process
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+
+	return &pooledBrowser{ctx: ctx, cancel: cancel}, nil
+}
+
+// alive probes a browser after a tab failed, to tell page errors from crashes.
+func alive(b *pooledBrowser) bool {
+	if b.ctx.Err() != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(b.ctx, healthCheckTimeout)
+	defer cancel()
+	c := chromedp.FromContext(ctx)
+	if c == nil || c.Browser == nil {
+		return false
+	}
+	_, _, _, _, _, err := browser.GetVersion().Do(cdp.WithExecutor(ctx, c.Browser))
+	return err == nil
+}
+
+// release returns a tab's browser to the pool, retiring it when it crashed or
+// has served its maximum number of tabs.
+func (p *BrowserPool) release(b *pooledBrowser, err error) {
+	crashed := err != nil && !errors.Is(err, ErrAppNotFound) && !alive(b)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b.active--
+	b.uses++
+
+	switch {
+	case b.retired:
+	case crashed:
+		log.Printf("Browser crashed, retiring it: %v", err)
+		b.retired = true
+		p.stats.Crashed++
+	case p.cfg.MaxUses > 0 && b.uses >= p.cfg.MaxUses:
+		log.Printf("Browser served %d tabs, recycling it", b.uses)
+		b.retired = true
+		p.stats.Recycled++
+	}
+	p.reap()
+}
+
+// reap stops retired browsers without open tabs. Callers must hold p.mu.
+func (p *BrowserPool) reap() {
+	kept := p.browsers[:0]
+	for _, b := range p.browsers {
+		if !b.retired && b.ctx.Err() != nil {
+			b.retired = true
+			p.stats.Crashed++
+		}
+		if b.retired && b.active == 0 {
+			b.cancel()
+			continue
+		}
+		kept = append(kept, b)
+	}
+	p.browsers = kept
+}
+
+// running counts browsers that still accept tabs. Callers must hold p.mu.
+func (p *BrowserPool) running() int {
+	n := 0
+	for _, b := range p.browsers {
+		if !b.retired {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrowserPoolQueuesWhenFull(t *testing.T) {
+	pool := NewBrowserPool(PoolConfig{Size: 1, MaxTabs: 1})
+	defer pool.Close()
+
+	// Occupy the only tab slot
+	pool.tabs <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := pool.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	stats := pool.Stats()
+	assert.Equal(t, 0, stats.Waiting)
+	assert.Equal(t, 1, stats.Active)
+	assert.Equal(t, 1, stats.MaxTabs)
+}
+
+func TestBrowserPoolClosed(t *testing.T) {
+	pool := NewBrowserPool(PoolConfig{Size: 1, MaxTabs: 1})
+	pool.Close()
+
+	_, _, err := pool.Acquire(context.Background())
+	assert.ErrorIs(t, err, ErrPoolClosed)
+	assert.Equal(t, 0, pool.Stats().Active)
+	assert.ErrorIs(t, pool.Warm(), ErrPoolClosed)
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// retryOperation retries a function with exponential backoff, giving up as soon as ctx is done
+func retryOperation(ctx context.Context, operation func(context.Context) (App, error), maxRetries int, operationName string) (App, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(attempt) * time.Second
+			log.Printf("%s: retry attempt %d/%d after %v", operationName, attempt+1, maxRetries, backoff)
+			if err := sleepContext(ctx, backoff); err != nil {
+				return App{}, fmt.Errorf("%s: gave up after %d attempts: %w: %w", operationName, attempt, err, lastErr)
+			}
+		}
+
+		app, err := operation(ctx)
+		if err == nil {
+			if attempt > 0 {
+				log.Printf("%s: succeeded on attempt %d/%d", operationName, attempt+1, maxRetries)
+			}
+			return app, nil
+		}
+
+		// Retrying a blocked client right away only prolongs the block
+		if !Retryable(err) || errors.Is(err, ErrBlocked) || ctx.Err() != nil {
+			return App{}, err
+		}
+
+		lastErr = err
+	}
+	return App{}, fmt.Errorf("%s: failed after %d attempts: %w", operationName, maxRetries, lastErr)
+}
+
+// sleepContext waits for d, returning early with the context error when ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
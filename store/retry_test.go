@@ -0,0 +1,37 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryOperationStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	start := time.Now()
+	_, err := retryOperation(ctx, func(context.Context) (App, error) {
+		attempts++
+		cancel()
+		return App{}, errors.New("scrape failed")
+	}, 3, "test")
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryOperationSkipsNotFound(t *testing.T) {
+	attempts := 0
+	_, err := retryOperation(context.Background(), func(context.Context) (App, error) {
+		attempts++
+		return App{}, ErrAppNotFound
+	}, 3, "test")
+
+	assert.ErrorIs(t, err, ErrAppNotFound)
+	assert.Equal(t, 1, attempts)
+}
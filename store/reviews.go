@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// Review sort orders accepted by every ReviewLister.
+const (
+	SortNewest  = "newest"
+	SortHelpful = "helpful"
+)
+
+// ReviewSorts lists the review sort orders, the default first.
+var ReviewSorts = []string{SortNewest, SortHelpful}
+
+// ReviewQuery holds the parameters of a page of reviews.
+type ReviewQuery struct {
+	// ID identifies the app as in a lookup: the App Store's track ID or Google Play's package name
+	ID      string
+	Lang    string
+	Country string
+	// Sort is SortNewest or SortHelpful
+	Sort string
+	// Page is the NextPage token of the previous page; empty for the first page
+	Page string
+}
+
+// Review is a user review of an app.
+type Review struct {
+	Date time.Time `json:"date"`
+	// Reply is the developer's answer, if any
+	Reply   *ReviewReply `json:"reply,omitempty"`
+	ID      string       `json:"id"`
+	Author  string       `json:"author"`
+	Title   string       `json:"title,omitempty"`
+	Body    string       `json:"body"`
+	Version string       `json:"version,omitempty"`
+	Rating  int          `json:"rating"`
+}
+
+// ReviewReply is a developer's reply to a review.
+type ReviewReply struct {
+	Date time.Time `json:"date"`
+	Body string    `json:"body"`
+}
+
+// ReviewPage is a page of reviews.
+type ReviewPage struct {
+	// NextPage is the opaque token of the next page; empty on the last page
+	NextPage string   `json:"nextPage,omitempty"`
+	Reviews  []Review `json:"reviews"`
+}
+
+// ReviewLister is implemented by stores that publish user reviews.
+type ReviewLister interface {
+	Reviews(ctx context.Context, q ReviewQuery) (ReviewPage, error)
+}
+
+// encodePageToken wraps a store's own paging state into an opaque token, so
+// callers never depend on whether a store pages by number or by cursor.
+func encodePageToken(state string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(state))
+}
+
+// decodePageToken returns the paging state of a token made by encodePageToken.
+func decodePageToken(token string) (string, error) {
+	state, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(state) == 0 {
+		return "", fmt.Errorf("%w: malformed page token %q", ErrInvalidInput, token)
+	}
+	return string(state), nil
+}
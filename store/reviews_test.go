@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageToken(t *testing.T) {
+	state, err := decodePageToken(encodePageToken("CpYBCpMB"))
+	require.NoError(t, err)
+	assert.Equal(t, "CpYBCpMB", state)
+
+	_, err = decodePageToken("not a token!")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = decodePageToken("")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestAppStoreReviews(t *testing.T) {
+	serveAppStoreFixtures(t)
+
+	page, err := AppStore{}.Reviews(context.Background(), ReviewQuery{ID: "1592213654"})
+	require.NoError(t, err)
+	require.Len(t, page.Reviews, 2)
+	assert.Equal(t, Review{
+		Date:    time.Date(2024, 5, 22, 16, 12, 33, 0, time.UTC),
+		ID:      "11223344556",
+		Author:  "jdoe",
+		Title:   "Does what it says",
+		Body:    "Tracks every store in one place.",
+		Version: "2.4.1",
+		Rating:  5,
+	}, page.Reviews[0])
+	assert.Equal(t, 2, page.Reviews[1].Rating)
+	assert.Equal(t, encodePageToken("2"), page.NextPage)
+
+	// The feed ends at page 10
+	page, err = AppStore{}.Reviews(context.Background(), ReviewQuery{
+		ID: "1592213654", Country: "gb", Sort: SortHelpful, Page: encodePageToken("10"),
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Reviews, 2)
+	assert.Empty(t, page.NextPage)
+
+	// Past the last review the feed has no entries
+	page, err = AppStore{}.Reviews(context.Background(), ReviewQuery{ID: "1592213654", Page: encodePageToken("3")})
+	require.NoError(t, err)
+	assert.Empty(t, page.Reviews)
+	assert.Empty(t, page.NextPage)
+
+	_, err = AppStore{}.Reviews(context.Background(), ReviewQuery{ID: "1592213654", Page: encodePageToken("11")})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = AppStore{}.Reviews(context.Background(), ReviewQuery{ID: "1592213654", Sort: "rating"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = AppStore{}.Reviews(context.Background(), ReviewQuery{ID: "com.arise.katsini"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = AppStore{}.Reviews(context.Background(), ReviewQuery{ID: "1592213654", Country: "us/x"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestPlayStoreReviews(t *testing.T) {
+	servePlayStoreFixtures(t)
+
+	page, err := PlayStore{}.Reviews(context.Background(), ReviewQuery{ID: "com.mediocre.dirac", Sort: SortHelpful})
+	require.NoError(t, err)
+	require.Len(t, page.Reviews, 2)
+	assert.Equal(t, Review{
+		Date: time.Unix(1717000000, 0).UTC(),
+		Reply: &ReviewReply{
+			Date: time.Unix(1717100000, 0).UTC(),
+			Body: "Thanks Ana!",
+		},
+		ID:      "gp:AOqpTOF1",
+		Author:  "Ana",
+		Body:    "Fun & hard.",
+		Version: "1.1.5",
+		Rating:  4,
+	}, page.Reviews[0])
+	assert.Nil(t, page.Reviews[1].Reply)
+	assert.Empty(t, page.Reviews[1].Version)
+	require.NotEmpty(t, page.NextPage)
+
+	page, err = PlayStore{}.Reviews(context.Background(), ReviewQuery{ID: "com.mediocre.dirac", Page: page.NextPage})
+	require.NoError(t, err)
+	require.Len(t, page.Reviews, 1)
+	assert.Equal(t, "gp:AOqpTOF3", page.Reviews[0].ID)
+	assert.Empty(t, page.NextPage)
+
+	_, err = PlayStore{}.Reviews(context.Background(), ReviewQuery{ID: "com.example.unknown"})
+	assert.ErrorIs(t, err, ErrAppNotFound)
+
+	_, err = PlayStore{}.Reviews(context.Background(), ReviewQuery{ID: "not a package"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -0,0 +1,18 @@
+package store
+
+import "context"
+
+// SearchQuery holds the parameters of an app search.
+type SearchQuery struct {
+	Term    string
+	Lang    string
+	Country string
+	Limit   int
+}
+
+// Searcher is implemented by stores that can search apps by name. Results
+// carry the identifiers needed for a lookup, plus whatever else the search
+// results of the store show.
+type Searcher interface {
+	Search(ctx context.Context, q SearchQuery) ([]App, error)
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppStoreSearch(t *testing.T) {
+	serveAppStoreFixtures(t)
+
+	apps, err := AppStore{}.Search(context.Background(), SearchQuery{Term: "katsini", Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "1592213654", apps[0].AppID)
+	assert.Equal(t, "com.arise.katsini", apps[0].BundleID)
+	assert.Equal(t, "2.4.1", apps[0].Version)
+
+	apps, err = AppStore{}.Search(context.Background(), SearchQuery{Term: "nothing"})
+	require.NoError(t, err)
+	assert.Empty(t, apps)
+}
+
+func TestPlayStoreSearch(t *testing.T) {
+	servePlayStoreFixtures(t)
+
+	apps, err := PlayStore{}.Search(context.Background(), SearchQuery{Term: "radio", Country: "de"})
+	require.NoError(t, err)
+	require.Len(t, apps, 3, "duplicates are listed once")
+	assert.Equal(t, App{
+		BundleID:  "com.radio.fmradio",
+		URL:       "https://play.google.com/store/apps/details?id=com.radio.fmradio&hl=en&gl=de",
+		Title:     "Radio FM",
+		Developer: "RADIOFM",
+		Icon:      "https://play-lh.googleusercontent.com/com.radio.fmradio",
+		Rating:    4.6,
+	}, apps[0])
+	assert.Equal(t, "com.jacapps.radio", apps[1].BundleID)
+	assert.InDelta(t, 2.49, apps[1].Price, 0.001)
+	assert.Equal(t, "EUR", apps[1].Currency)
+	assert.Equal(t, "de.radio.android", apps[2].BundleID)
+
+	apps, err = PlayStore{}.Search(context.Background(), SearchQuery{Term: "radio", Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, apps, 2)
+}
+
+func TestAppGallerySearch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "searchApp|radio", r.URL.Query().Get("uri"))
+		page, err := os.ReadFile(filepath.Join("testdata", "appgallery", "search.json"))
+		if err != nil {
+			t.Errorf("Failed to read fixture: %v", err)
+		}
+		_, _ = w.Write(page)
+	}))
+	t.Cleanup(srv.Close)
+
+	original := appGalleryWebAPI
+	appGalleryWebAPI = srv.URL
+	t.Cleanup(func() { appGalleryWebAPI = original })
+
+	apps, err := AppGallery{}.Search(context.Background(), SearchQuery{Term: "radio"})
+	require.NoError(t, err)
+	require.Len(t, apps, 2, "banners are skipped")
+	assert.Equal(t, App{
+		AppID:     "100102149",
+		BundleID:  "com.radio.fmradio",
+		URL:       "https://appgallery.huawei.com/app/C100102149",
+		Title:     "Radio FM",
+		Version:   "6.5.6",
+		Developer: "RADIOFM",
+		Installs:  "10 M",
+		Icon:      "https://appimg.dbankcdn.com/radiofm.png",
+		Category:  "Music & audio",
+		Rating:    4.5,
+	}, apps[0])
+	assert.InDelta(t, 4.2, apps[1].Rating, 0)
+}
@@ -0,0 +1,96 @@
+package store
+
+import "context"
+
+// Identifier is the name of a query parameter that identifies an app in a store.
+type Identifier string
+
+const (
+	IdentifierAppID    Identifier = "appId"
+	IdentifierBundleID Identifier = "bundleId"
+)
+
+// Query holds the parameters of a single app lookup.
+type Query struct {
+	AppID    string
+	BundleID string
+	Lang     string
+	Country  string
+}
+
+// ID returns the value of the given identifier in the query.
+func (q Query) ID(id Identifier) string {
+	switch id {
+	case IdentifierAppID:
+		return q.AppID
+	case IdentifierBundleID:
+		return q.BundleID
+	default:
+		return ""
+	}
+}
+
+// Store is implemented by every supported app store.
+// Adding a store only requires implementing this interface and listing it in Default.
+type Store interface {
+	// Name is the short, URL-safe name of the store, also used as its route.
+	Name() string
+	// Title is the human readable name of the store.
+	Title() string
+	// Identifiers lists the identifiers the store accepts; at least one must be provided.
+	Identifiers() []Identifier
+	// Options lists the optional query parameters the store understands.
+	Options() []string
+	// Lookup fetches the app matching the query.
+	Lookup(ctx context.Context, q Query) (App, error)
+}
+
+// Registry keeps the registered stores in registration order.
+type Registry struct {
+	byName map[string]Store
+	stores []Store
+}
+
+// NewRegistry creates a registry containing the given stores.
+func NewRegistry(stores ...Store) *Registry {
+	r := &Registry{byName: make(map[string]Store, len(stores))}
+	for _, s := range stores {
+		r.Register(s)
+	}
+	return r
+}
+
+// Default creates a registry with every supported store, reading the Play
+// Store mode from PLAYSTORE_MODE.
+func Default() *Registry {
+	return NewRegistry(
+		PlayStore{Mode: PlayModeFromEnv()},
+		AppStore{},
+		AppGallery{},
+	)
+}
+
+// Register adds a store to the registry, replacing any store with the same name.
+func (r *Registry) Register(s Store) {
+	if _, ok := r.byName[s.Name()]; ok {
+		for i, existing := range r.stores {
+			if existing.Name() == s.Name() {
+				r.stores[i] = s
+			}
+		}
+	} else {
+		r.stores = append(r.stores, s)
+	}
+	r.byName[s.Name()] = s
+}
+
+// Get returns the store registered under name.
+func (r *Registry) Get(name string) (Store, bool) {
+	s, ok := r.byName[name]
+	return s, ok
+}
+
+// Stores returns all registered stores in registration order.
+func (r *Registry) Stores() []Store {
+	return r.stores
+}
@@ -1,6 +1,7 @@
-package main
+package store
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -41,11 +42,11 @@ func TestGooglePlayStore(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.title, func(t *testing.T) {
-			app, err := GooglePlayStore(tc.bundleID, "en", "us")
+			app, err := GooglePlayStore(context.Background(), tc.bundleID, "en", "us")
 			assert.NoError(t, err)
-			assert.Equal(t, tc.title, app.title)
-			assert.Equal(t, tc.url, app.url)
-			assert.Equal(t, tc.developer, app.developer)
+			assert.Equal(t, tc.title, app.Title)
+			assert.Equal(t, tc.url, app.URL)
+			assert.Equal(t, tc.developer, app.Developer)
 		})
 	}
 }
@@ -90,13 +91,13 @@ func TestAppleAppStore(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.title, func(t *testing.T) {
-			app, err := AppleAppStore(tc.appID, tc.bundleID, "us")
+			app, err := AppleAppStore(context.Background(), tc.appID, tc.bundleID, "us")
 			assert.NoError(t, err)
-			assert.Equal(t, tc.appID, app.appID)
-			assert.Equal(t, tc.bundleID, app.bundleID)
-			assert.Equal(t, tc.title, app.title)
-			assert.Equal(t, tc.url, app.url)
-			assert.Equal(t, tc.developer, app.developer)
+			assert.Equal(t, tc.appID, app.AppID)
+			assert.Equal(t, tc.bundleID, app.BundleID)
+			assert.Equal(t, tc.title, app.Title)
+			assert.Equal(t, tc.url, app.URL)
+			assert.Equal(t, tc.developer, app.Developer)
 		})
 	}
 }
@@ -141,33 +142,33 @@ func TestHuaweiAppGallery(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.title, func(t *testing.T) {
-			app, err := HuaweiAppGallery(tc.appID)
+			app, err := HuaweiAppGallery(context.Background(), tc.appID)
 			assert.NoError(t, err)
-			assert.Equal(t, tc.title, app.title)
-			assert.Equal(t, tc.bundleID, app.bundleID)
-			assert.Equal(t, tc.url, app.url)
-			assert.Equal(t, tc.developer, app.developer)
+			assert.Equal(t, tc.title, app.Title)
+			assert.Equal(t, tc.bundleID, app.BundleID)
+			assert.Equal(t, tc.url, app.URL)
+			assert.Equal(t, tc.developer, app.Developer)
 		})
 	}
 }
 
 func BenchmarkGooglePlayStore(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, err := GooglePlayStore("com.gianlu.timeless", "en", "us")
+		_, err := GooglePlayStore(context.Background(), "com.gianlu.timeless", "en", "us")
 		assert.NoError(b, err)
 	}
 }
 
 func BenchmarkAppleAppStore(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, err := AppleAppStore("1602926022", "", "us")
+		_, err := AppleAppStore(context.Background(), "1602926022", "", "us")
 		assert.NoError(b, err)
 	}
 }
 
 func BenchmarkHuaweiAppGallery(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, err := HuaweiAppGallery("106093011")
+		_, err := HuaweiAppGallery(context.Background(), "106093011")
 		assert.NoError(b, err)
 	}
 }
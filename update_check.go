@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/arisecode/katsini/cache"
+)
+
+// updateCheck is the response of GET /update-check.
+type updateCheck struct {
+	Store   string `json:"store"`
+	ID      string `json:"id"`
+	Current string `json:"current"`
+	Version string `json:"version"`
+	Updated string `json:"updated"`
+	// Bump is "major", "minor" or "patch"; omitted without an update
+	Bump            string `json:"bump,omitempty"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+}
+
+// handleUpdateCheck tells an app whether the store has a newer version than
+// the one it runs. Lookups go through the cache, falling back to stale data
+// like lookups do, so clients can call it at every start.
+func (s *server) handleUpdateCheck() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		st, ok := s.registry.Get(query.Get("store"))
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+
+		id := query.Get("id")
+		if id == "" {
+			writeInvalidInput(w, r, st.Name(), "Please provide an app id")
+			return
+		}
+		current := query.Get("current")
+		currentVersion, ok := parseVersion(current)
+		if !ok {
+			writeInvalidInput(w, r, st.Name(), "Please provide the current version, such as 1.4.2")
+			return
+		}
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxTimeout)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		q := storeQuery(st, id, query.Get("lang"), query.Get("country"))
+		entry, hit, err := s.lookup(ctx, st, q, false)
+		if err != nil {
+			stale, ok := s.staleEntry(cache.KeyFor(st.Name(), q), err)
+			if !ok {
+				writeLookupError(w, r, st.Name(), err)
+				return
+			}
+			log.Printf("Checking updates against stale %s data for %+v: %v", st.Name(), q, err)
+			w.Header().Set("X-Cache", "STALE")
+			entry = stale
+		} else {
+			writeCacheHeaders(w, entry, hit)
+		}
+
+		check := updateCheck{
+			Store:   st.Name(),
+			ID:      id,
+			Current: current,
+			Version: entry.App.Version,
+			Updated: entry.App.Updated,
+		}
+		// Store versions without numbers, such as "Varies with device", never report an update
+		if latest, ok := parseVersion(entry.App.Version); ok {
+			check.Bump = latest.bump(currentVersion)
+			check.UpdateAvailable = check.Bump != ""
+		}
+		writeJSON(w, http.StatusOK, check)
+	}
+}
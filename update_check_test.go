@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/cache"
+	"github.com/arisecode/katsini/store"
+)
+
+func TestUpdateCheckHandler(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", Version: "1.13", Updated: "02-03-2025"}}
+	srv := newTestServer(t, fake)
+	mux := srv.routes()
+
+	check := func(current string) (updateCheck, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/update-check?store=fake&id=1&current="+current, http.NoBody)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var body updateCheck
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		return body, rr
+	}
+
+	body, rr := check("1.4.2")
+	assert.Equal(t, updateCheck{
+		Store:           "fake",
+		ID:              "1",
+		Current:         "1.4.2",
+		Version:         "1.13",
+		Updated:         "02-03-2025",
+		Bump:            bumpMinor,
+		UpdateAvailable: true,
+	}, body)
+	assert.Equal(t, "MISS", rr.Header().Get("X-Cache"))
+
+	body, rr = check("1.13.0-rc1")
+	assert.False(t, body.UpdateAvailable)
+	assert.Empty(t, body.Bump)
+	assert.Equal(t, "HIT", rr.Header().Get("X-Cache"))
+	assert.Equal(t, 1, fake.calls, "update checks must be served from the cache")
+
+	body, _ = check("0.9")
+	assert.Equal(t, bumpMajor, body.Bump)
+}
+
+func TestUpdateCheckHandlerStale(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", Version: "Varies with device"}}
+	srv := newTestServer(t, fake)
+	srv.cache = cache.New(time.Nanosecond, nil, time.Hour, 0)
+	mux := srv.routes()
+
+	check := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/update-check?store=fake&id=1&current=1.0", http.NoBody)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := check()
+	require.Equal(t, http.StatusOK, rr.Code)
+	var body updateCheck
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.False(t, body.UpdateAvailable, "versions without numbers never report an update")
+
+	fake.err = errors.New("failed to extract app data")
+	time.Sleep(time.Millisecond)
+	rr = check()
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "STALE", rr.Header().Get("X-Cache"))
+
+	fake.err = store.ErrAppNotFound
+	assert.Equal(t, http.StatusNotFound, check().Code)
+}
+
+func TestUpdateCheckHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&id=1&current=1.0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Missing id",
+			query:          "?store=fake&current=1.0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide an app id"},
+		},
+		{
+			name:           "Invalid current version",
+			query:          "?store=fake&id=1&current=latest",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide the current version, such as 1.4.2"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/update-check"+tt.query, http.NoBody)
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version is the numeric part of an app version, one number per dot-separated segment.
+type version []int
+
+// parseVersion reads the leading dot-separated numbers of a store version,
+// ignoring a "v" prefix and whatever follows the numbers, such as build
+// suffixes in "2.1.0-beta" or "5.3 (1234)". It fails for versions without a
+// leading number, such as Play's "Varies with device".
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(s)), "v")
+
+	var v version
+	for segment := range strings.SplitSeq(s, ".") {
+		end := strings.IndexFunc(segment, func(r rune) bool { return r < '0' || r > '9' })
+		digits := segment
+		if end >= 0 {
+			digits = segment[:end]
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			break
+		}
+		v = append(v, n)
+		if end >= 0 {
+			break
+		}
+	}
+	return v, len(v) > 0
+}
+
+// compare returns -1, 0 or +1 as v is older, equal to or newer than o.
+// Missing segments count as 0, so 1.13 equals 1.13.0.
+func (v version) compare(o version) int {
+	for i := range max(len(v), len(o)) {
+		a, b := v.segment(i), o.segment(i)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Version bumps, named after the first segment that changed.
+const (
+	bumpMajor = "major"
+	bumpMinor = "minor"
+	bumpPatch = "patch"
+)
+
+// bump returns the kind of update from o to v: bumpMajor or bumpMinor when
+// the first or second number grew, bumpPatch when a later one did, and ""
+// when v is not newer than o.
+func (v version) bump(o version) string {
+	for i := range max(len(v), len(o)) {
+		a, b := v.segment(i), o.segment(i)
+		if a == b {
+			continue
+		}
+		if a < b {
+			return ""
+		}
+		switch i {
+		case 0:
+			return bumpMajor
+		case 1:
+			return bumpMinor
+		default:
+			return bumpPatch
+		}
+	}
+	return ""
+}
+
+// segment returns the i-th number of the version, or 0 past its end.
+func (v version) segment(i int) int {
+	if i < len(v) {
+		return v[i]
+	}
+	return 0
+}
+
+// sameVersion reports whether two store versions are the same release,
+// comparing their numbers when both parse and their text otherwise.
+func sameVersion(a, b string) bool {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if okA && okB {
+		return va.compare(vb) == 0
+	}
+	return a == b
+}
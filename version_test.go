@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  version
+		ok    bool
+	}{
+		{"1.4.2", version{1, 4, 2}, true},
+		{"1.13", version{1, 13}, true},
+		{"6.5.6.300", version{6, 5, 6, 300}, true},
+		{"v2.0", version{2, 0}, true},
+		{"2.1.0-beta.3", version{2, 1, 0}, true},
+		{"5.3 (1234)", version{5, 3}, true},
+		{"3.2.1+build.7", version{3, 2, 1}, true},
+		{"10", version{10}, true},
+		{"Varies with device", nil, false},
+		{"", nil, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := parseVersion(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := func(s string) version {
+		parsed, ok := parseVersion(s)
+		assert.True(t, ok, s)
+		return parsed
+	}
+
+	assert.Equal(t, 0, v("1.13").compare(v("1.13.0")))
+	assert.Equal(t, -1, v("1.9").compare(v("1.13")))
+	assert.Equal(t, 1, v("6.5.6.300").compare(v("6.5.6")))
+	assert.Equal(t, -1, v("2.0.0-beta").compare(v("2.0.1")))
+
+	assert.True(t, sameVersion("1.13", "1.13.0"))
+	assert.False(t, sameVersion("1.13", "1.14"))
+	assert.True(t, sameVersion("Varies with device", "Varies with device"))
+	assert.False(t, sameVersion("Varies with device", "1.0"))
+}
+
+func TestVersionBump(t *testing.T) {
+	testCases := []struct {
+		latest  string
+		current string
+		want    string
+	}{
+		{"2.0", "1.4.2", bumpMajor},
+		{"1.13", "1.4.2", bumpMinor},
+		{"1.4.3", "1.4.2", bumpPatch},
+		{"6.5.6.300", "6.5.6.200", bumpPatch},
+		{"6.5.6.300", "6.5.6", bumpPatch},
+		{"1.4.2 (512)", "1.4.2", ""},
+		{"1.13.0", "1.13", ""},
+		{"1.4.1", "1.4.2", ""},
+		{"1.9", "2.0-beta", ""},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.latest+" from "+tt.current, func(t *testing.T) {
+			latest, _ := parseVersion(tt.latest)
+			current, _ := parseVersion(tt.current)
+			assert.Equal(t, tt.want, latest.bump(current))
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arisecode/katsini/cache"
+	"github.com/arisecode/katsini/store"
+)
+
+const (
+	// waitBackoff is the wait between the first two checks of a waited for app
+	waitBackoff = 5 * time.Second
+	// maxWaitBackoff caps the wait between two checks of a waited for app
+	maxWaitBackoff = time.Minute
+	// waitWriteSlack leaves time to write the response once a wait timed out
+	waitWriteSlack = 5 * time.Second
+)
+
+// poller checks an app on a backoff schedule for every request waiting on it,
+// so concurrent waiters share one check per interval.
+type poller struct {
+	// checked is closed and replaced after every check
+	checked chan struct{}
+	cancel  context.CancelFunc
+	err     error
+	last    store.App
+	checks  int
+	waiters int
+}
+
+// pollers tracks the apps being polled for waiting requests.
+type pollers struct {
+	polls      map[cache.Key]*poller
+	backoff    time.Duration
+	maxBackoff time.Duration
+	mu         sync.Mutex
+}
+
+// newPollers creates the poller registry, waiting backoff between the first
+// two checks of an app and doubling the wait up to maxBackoff.
+func newPollers(backoff, maxBackoff time.Duration) *pollers {
+	return &pollers{polls: make(map[cache.Key]*poller), backoff: backoff, maxBackoff: maxBackoff}
+}
+
+// handleWait holds the request until the store serves the given version of an
+// app, or a newer one, answering 408 with the last observed version when the
+// timeout expires first.
+func (s *server) handleWait() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		query := r.URL.Query()
+		st, ok := s.registry.Get(query.Get("store"))
+		if !ok {
+			writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+			return
+		}
+
+		id := query.Get("id")
+		if id == "" {
+			writeInvalidInput(w, r, st.Name(), "Please provide an app id")
+			return
+		}
+		want := query.Get("version")
+		if want == "" {
+			writeInvalidInput(w, r, st.Name(), "Please provide the version to wait for")
+			return
+		}
+
+		timeout, err := lookupTimeout(query.Get("timeout"), s.cfg.maxWait)
+		if err != nil {
+			writeInvalidInput(w, r, st.Name(), err.Error())
+			return
+		}
+
+		// Waits outlast the server's write timeout, which is sized for single lookups
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + waitWriteSlack))
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		q := storeQuery(st, id, query.Get("lang"), query.Get("country"))
+		app, err := s.waitForVersion(ctx, st, q, want)
+		switch {
+		case err == nil:
+			writeJSON(w, http.StatusOK, map[string]any{
+				"store":   st.Name(),
+				"id":      id,
+				"version": app.Version,
+				"live":    true,
+				"app":     app,
+			})
+		case errors.Is(err, context.DeadlineExceeded):
+			writeJSON(w, http.StatusRequestTimeout, map[string]any{
+				"store":   st.Name(),
+				"id":      id,
+				"version": app.Version,
+				"live":    false,
+				"error":   fmt.Sprintf("Version %s is not live after %v", want, timeout),
+			})
+		case errors.Is(err, context.Canceled):
+			// The client went away; nobody reads the response
+		default:
+			writeLookupError(w, r, st.Name(), err)
+		}
+	}
+}
+
+// waitForVersion returns the app once a check observes the wanted version or
+// a newer one. When ctx ends first, it returns the last observed app with the
+// context error. Missing apps are polled on, since the first release of an app
+// is not live yet either; only malformed queries end the wait right away.
+func (s *server) waitForVersion(ctx context.Context, st store.Store, q store.Query, want string) (store.App, error) {
+	key := cache.KeyFor(st.Name(), q)
+	p := s.joinPoller(st, q, key)
+	defer s.leavePoller(key, p)
+
+	for {
+		s.waits.mu.Lock()
+		checked, app, err, checks := p.checked, p.last, p.err, p.checks
+		s.waits.mu.Unlock()
+
+		if checks > 0 {
+			if err == nil && versionLive(app.Version, want) {
+				return app, nil
+			}
+			if errors.Is(err, store.ErrInvalidInput) {
+				return app, err
+			}
+		}
+
+		select {
+		case <-checked:
+		case <-ctx.Done():
+			return app, ctx.Err()
+		}
+	}
+}
+
+// joinPoller registers a waiter on the poller of an app, starting it when the
+// app is not polled yet.
+func (s *server) joinPoller(st store.Store, q store.Query, key cache.Key) *poller {
+	s.waits.mu.Lock()
+	defer s.waits.mu.Unlock()
+
+	p, ok := s.waits.polls[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		p = &poller{checked: make(chan struct{}), cancel: cancel}
+		s.waits.polls[key] = p
+		go s.poll(ctx, st, q, p)
+	}
+	p.waiters++
+	return p
+}
+
+// leavePoller unregisters a waiter, stopping the poller after the last one.
+func (s *server) leavePoller(key cache.Key, p *poller) {
+	s.waits.mu.Lock()
+	defer s.waits.mu.Unlock()
+
+	p.waiters--
+	if p.waiters == 0 {
+		p.cancel()
+		delete(s.waits.polls, key)
+	}
+}
+
+// poll checks an app until ctx is done, doubling the wait between checks. The
+// first check may be answered by the cache; later ones fetch the store.
+func (s *server) poll(ctx context.Context, st store.Store, q store.Query, p *poller) {
+	backoff := s.waits.backoff
+	refresh := false
+	for {
+		lookupCtx, cancel := context.WithTimeout(ctx, s.cfg.maxTimeout)
+		entry, _, err := s.lookup(lookupCtx, st, q, refresh)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.waits.mu.Lock()
+		if err == nil {
+			p.last = entry.App
+		}
+		p.err = err
+		p.checks++
+		close(p.checked)
+		p.checked = make(chan struct{})
+		s.waits.mu.Unlock()
+
+		refresh = true
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, s.waits.maxBackoff)
+	}
+}
+
+// versionLive reports whether an observed store version is the wanted
+// version or a newer one. Versions that do not parse must match exactly.
+func versionLive(observed, want string) bool {
+	o, okObserved := parseVersion(observed)
+	w, okWant := parseVersion(want)
+	if okObserved && okWant {
+		return o.compare(w) >= 0
+	}
+	return observed == want
+}
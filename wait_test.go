@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/store"
+)
+
+// releaseStore is an offline store serving a version that tests can bump
+// while requests wait on it
+type releaseStore struct {
+	fakeStore
+	err     error
+	version string
+	calls   int
+	mu      sync.Mutex
+}
+
+func (r *releaseStore) Lookup(_ context.Context, _ store.Query) (store.App, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+	return store.App{AppID: "1", Version: r.version}, r.err
+}
+
+func (r *releaseStore) release(version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.version = version
+	r.err = nil
+}
+
+func (r *releaseStore) lookups() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func newWaitServer(t *testing.T, st store.Store) *server {
+	t.Helper()
+
+	srv := newTestServer(t, st)
+	srv.cfg.maxWait = time.Minute
+	srv.waits = newPollers(5*time.Millisecond, 10*time.Millisecond)
+	return srv
+}
+
+func waitRequest(srv *server, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/wait"+query, http.NoBody)
+	rr := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rr, req)
+	return rr
+}
+
+func TestWaitHandlerReturnsOnceLive(t *testing.T) {
+	fake := &releaseStore{version: "1.4.2"}
+	srv := newWaitServer(t, fake)
+
+	done := make(chan *httptest.ResponseRecorder)
+	for range 3 {
+		go func() { done <- waitRequest(srv, "?store=fake&id=1&version=1.5&timeout=5s") }()
+	}
+
+	require.Eventually(t, func() bool { return fake.lookups() >= 2 }, time.Second, time.Millisecond)
+	fake.release("1.5.0")
+
+	for range 3 {
+		rr := <-done
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		assert.Equal(t, "1.5.0", body["version"])
+		assert.Equal(t, true, body["live"])
+	}
+
+	srv.waits.mu.Lock()
+	defer srv.waits.mu.Unlock()
+	assert.Empty(t, srv.waits.polls, "pollers must stop once every waiter left")
+}
+
+func TestWaitHandlerSharesChecks(t *testing.T) {
+	fake := &releaseStore{version: "1.0"}
+	srv := newWaitServer(t, fake)
+	srv.waits = newPollers(time.Hour, time.Hour)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			rr := waitRequest(srv, "?store=fake&id=1&version=2.0&timeout=50ms")
+			assert.Equal(t, http.StatusRequestTimeout, rr.Code)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, fake.lookups(), "concurrent waiters must share one check")
+}
+
+func TestWaitHandlerTimeout(t *testing.T) {
+	fake := &releaseStore{version: "1.4.2"}
+	srv := newWaitServer(t, fake)
+
+	rr := waitRequest(srv, "?store=fake&id=1&version=1.5&timeout=50ms")
+	require.Equal(t, http.StatusRequestTimeout, rr.Code)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Equal(t, "1.4.2", body["version"])
+	assert.Equal(t, false, body["live"])
+	assert.Equal(t, "Version 1.5 is not live after 50ms", body["error"])
+}
+
+func TestWaitHandlerFirstRelease(t *testing.T) {
+	fake := &releaseStore{err: store.ErrAppNotFound}
+	srv := newWaitServer(t, fake)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- waitRequest(srv, "?store=fake&id=1&version=1.0&timeout=5s") }()
+
+	require.Eventually(t, func() bool { return fake.lookups() >= 2 }, time.Second, time.Millisecond)
+	fake.release("1.0")
+
+	rr := <-done
+	assert.Equal(t, http.StatusOK, rr.Code, "missing apps must be polled until they are released")
+}
+
+func TestWaitHandlerInvalidInput(t *testing.T) {
+	fake := &releaseStore{err: fmt.Errorf("%w: malformed appId", store.ErrInvalidInput)}
+	srv := newWaitServer(t, fake)
+
+	rr := waitRequest(srv, "?store=fake&id=1&version=1.5&timeout=5s")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 1, fake.lookups(), "lookups that cannot succeed must end the wait")
+}
+
+func TestWaitHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Unknown store",
+			query:          "?store=unknown&id=1&version=1.0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Missing id",
+			query:          "?store=fake&version=1.0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide an app id"},
+		},
+		{
+			name:           "Missing version",
+			query:          "?store=fake&id=1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide the version to wait for"},
+		},
+		{
+			name:           "Invalid timeout",
+			query:          "?store=fake&id=1&version=1.0&timeout=soon",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": `invalid timeout "soon"`},
+		},
+	}
+
+	srv := newWaitServer(t, &releaseStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			checkResponse(t, waitRequest(srv, tt.query), tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+func TestVersionLive(t *testing.T) {
+	assert.True(t, versionLive("1.5.0", "1.5"))
+	assert.True(t, versionLive("1.6", "1.5"))
+	assert.False(t, versionLive("1.4.9", "1.5"))
+	assert.True(t, versionLive("Varies with device", "Varies with device"))
+	assert.False(t, versionLive("Varies with device", "1.5"))
+}
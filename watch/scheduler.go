@@ -0,0 +1,182 @@
+// Package watch refreshes watched apps in the background.
+package watch
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+// Checker looks up the current state of a watched app.
+type Checker func(ctx context.Context, w storage.Watch) (store.App, error)
+
+// ChangeFunc is called when a check finds a new version or update date of a
+// watched app. It must not block.
+type ChangeFunc func(w storage.Watch, old, updated store.App, detectedAt time.Time)
+
+// Config configures a Scheduler.
+type Config struct {
+	// StoreConcurrency overrides Concurrency per store name.
+	StoreConcurrency map[string]int
+	// OnChange, if set, is notified of every changed version or update date.
+	OnChange ChangeFunc
+	// Tick is how often the scheduler looks for due watches.
+	Tick time.Duration
+	// Concurrency is the default number of concurrent checks per store.
+	Concurrency int
+	// Jitter spreads checks by up to this fraction of their interval, so
+	// watches created together do not hit a store at the same time.
+	Jitter float64
+}
+
+// Scheduler periodically checks every due watch and records the result.
+type Scheduler struct {
+	db      *storage.DB
+	check   Checker
+	limits  map[string]chan struct{}
+	running map[int64]bool
+	now     func() time.Time
+	jitter  func() float64
+	cfg     Config
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+// New creates a scheduler checking the watches stored in db.
+func New(db *storage.DB, check Checker, cfg Config) *Scheduler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = 10 * time.Second
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+
+	return &Scheduler{
+		db:      db,
+		check:   check,
+		limits:  make(map[string]chan struct{}),
+		running: make(map[int64]bool),
+		now:     time.Now,
+		jitter:  rand.Float64, //nolint:gosec // Scheduling jitter does not need a secure source
+		cfg:     cfg,
+	}
+}
+
+// Run checks due watches until ctx is done, then waits for running checks.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Tick)
+	defer ticker.Stop()
+
+	for {
+		s.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// NextCheck returns when a watch checked at checkedAt is due again.
+func (s *Scheduler) NextCheck(checkedAt time.Time, interval time.Duration) time.Time {
+	// Uniform in [-Jitter, +Jitter] of the interval
+	offset := time.Duration((s.jitter()*2 - 1) * s.cfg.Jitter * float64(interval))
+	return checkedAt.Add(interval + offset)
+}
+
+// poll starts a check for every due watch that is not already running.
+func (s *Scheduler) poll(ctx context.Context) {
+	watches, err := s.db.DueWatches(ctx, s.now())
+	if err != nil {
+		log.Printf("Failed to load due watches: %v", err)
+		return
+	}
+
+	for _, w := range watches {
+		s.mu.Lock()
+		if s.running[w.ID] {
+			s.mu.Unlock()
+			continue
+		}
+		s.running[w.ID] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.running, w.ID)
+				s.mu.Unlock()
+			}()
+			s.run(ctx, w)
+		}()
+	}
+}
+
+// run checks a single watch once a slot of its store is free.
+func (s *Scheduler) run(ctx context.Context, w storage.Watch) {
+	limit := s.limit(w.Store)
+	select {
+	case limit <- struct{}{}:
+		defer func() { <-limit }()
+	case <-ctx.Done():
+		return
+	}
+
+	app, err := s.check(ctx, w)
+	if ctx.Err() != nil {
+		// Shutting down; the watch stays due and is checked after the restart
+		return
+	}
+
+	checkedAt := s.now()
+	next := s.NextCheck(checkedAt, time.Duration(w.Interval))
+	if err != nil {
+		log.Printf("Watch %d on %s failed: %v", w.ID, w.Store, err)
+		if err := s.db.UpdateWatchResult(ctx, w.ID, checkedAt, next, nil, err); err != nil {
+			log.Printf("Failed to record watch %d: %v", w.ID, err)
+		}
+		return
+	}
+
+	if err := s.db.UpdateWatchResult(ctx, w.ID, checkedAt, next, &app, nil); err != nil {
+		log.Printf("Failed to record watch %d: %v", w.ID, err)
+		return
+	}
+
+	if s.cfg.OnChange != nil && Changed(w.LastResult, app) {
+		s.cfg.OnChange(w, *w.LastResult, app, checkedAt)
+	}
+}
+
+// Changed reports whether app has a different version or update date than the
+// previous result. The first result of a watch is not a change.
+func Changed(previous *store.App, app store.App) bool {
+	return previous != nil && (previous.Version != app.Version || previous.Updated != app.Updated)
+}
+
+// limit returns the semaphore bounding concurrent checks on a store.
+func (s *Scheduler) limit(storeName string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.limits[storeName]; ok {
+		return l
+	}
+
+	n := s.cfg.Concurrency
+	if override, ok := s.cfg.StoreConcurrency[storeName]; ok && override > 0 {
+		n = override
+	}
+	l := make(chan struct{}, n)
+	s.limits[storeName] = l
+	return l
+}
@@ -0,0 +1,151 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+// openTestDB opens an empty database in a temporary directory
+func openTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+
+	db, err := storage.Open(context.Background(), filepath.Join(t.TempDir(), "katsini.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+// createWatch stores a watch that is due now
+func createWatch(t *testing.T, db *storage.DB, storeName, appID string, now time.Time) storage.Watch {
+	t.Helper()
+
+	w, err := db.CreateWatch(context.Background(), storage.Watch{
+		Store:     storeName,
+		AppID:     appID,
+		Interval:  storage.Duration(time.Hour),
+		CreatedAt: now,
+		NextCheck: now,
+	})
+	require.NoError(t, err)
+	return w
+}
+
+func TestSchedulerRecordsResults(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ok := createWatch(t, db, "appstore", "1", now)
+	failing := createWatch(t, db, "appstore", "2", now)
+	later := createWatch(t, db, "appstore", "3", now.Add(time.Minute))
+
+	s := New(db, func(_ context.Context, w storage.Watch) (store.App, error) {
+		if w.AppID == "2" {
+			return store.App{}, errors.New("blocked")
+		}
+		return store.App{AppID: w.AppID, Version: "1.0"}, nil
+	}, Config{Concurrency: 1, Jitter: 0.1})
+	s.now = func() time.Time { return now }
+	s.jitter = func() float64 { return 1 }
+
+	s.poll(context.Background())
+	s.wg.Wait()
+
+	got, err := db.Watch(context.Background(), ok.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.LastResult)
+	assert.Equal(t, "1.0", got.LastResult.Version)
+	assert.Equal(t, now, *got.LastChecked)
+	assert.Equal(t, now.Add(66*time.Minute), got.NextCheck, "next check must include the jitter")
+	assert.Empty(t, got.LastError)
+
+	got, err = db.Watch(context.Background(), failing.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.LastResult)
+	assert.Equal(t, "blocked", got.LastError)
+
+	got, err = db.Watch(context.Background(), later.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.LastChecked, "watches that are not due must not be checked")
+}
+
+func TestSchedulerRespectsStoreConcurrency(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+	for _, id := range []string{"1", "2", "3", "4"} {
+		createWatch(t, db, "playstore", id, now)
+	}
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	s := New(db, func(context.Context, storage.Watch) (store.App, error) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return store.App{Version: "1.0"}, nil
+	}, Config{Concurrency: 4, StoreConcurrency: map[string]int{"playstore": 2}})
+
+	s.poll(context.Background())
+	s.wg.Wait()
+
+	assert.Equal(t, 2, peak)
+
+	watches, err := db.Watches(context.Background())
+	require.NoError(t, err)
+	for _, w := range watches {
+		assert.NotNil(t, w.LastResult)
+	}
+}
+
+func TestSchedulerReportsChanges(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	w := createWatch(t, db, "appstore", "1", now)
+
+	version := "1.0"
+	type change struct{ old, updated string }
+	var changes []change
+	s := New(db, func(_ context.Context, w storage.Watch) (store.App, error) {
+		return store.App{AppID: w.AppID, Version: version, Updated: "01-01-2025"}, nil
+	}, Config{OnChange: func(_ storage.Watch, old, updated store.App, detectedAt time.Time) {
+		assert.Equal(t, now, detectedAt)
+		changes = append(changes, change{old.Version, updated.Version})
+	}})
+	s.now = func() time.Time { return now }
+
+	check := func() {
+		got, err := db.Watch(context.Background(), w.ID)
+		require.NoError(t, err)
+		s.run(context.Background(), got)
+	}
+
+	check()
+	check()
+	version = "1.1"
+	check()
+
+	assert.Equal(t, []change{{"1.0", "1.1"}}, changes, "only the version change must be reported")
+}
+
+func TestChanged(t *testing.T) {
+	app := store.App{Version: "1.0", Updated: "01-01-2025"}
+	assert.False(t, Changed(nil, app))
+	assert.False(t, Changed(&app, app))
+	assert.True(t, Changed(&app, store.App{Version: "1.1", Updated: "01-01-2025"}))
+	assert.True(t, Changed(&app, store.App{Version: "1.0", Updated: "02-01-2025"}))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+const (
+	// defaultWatchInterval is how often a watch is checked unless given an interval
+	defaultWatchInterval = time.Hour
+	// minWatchInterval keeps watches from scraping a store more often than the cache expires
+	minWatchInterval = time.Minute
+	// watchJitter spreads checks by up to 10% of their interval
+	watchJitter = 0.1
+)
+
+// watchRequest is the body of POST /watches.
+type watchRequest struct {
+	ReviewAlerts *storage.ReviewAlerts `json:"reviewAlerts"`
+	Store        string                `json:"store"`
+	ID           string                `json:"id"`
+	Lang         string                `json:"lang"`
+	Country      string                `json:"country"`
+	Interval     storage.Duration      `json:"interval"`
+}
+
+// handleWatches lists, creates and deletes the apps refreshed in the background.
+func (s *server) handleWatches() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.listWatches(w, r)
+		case http.MethodPost:
+			s.createWatch(w, r)
+		case http.MethodDelete:
+			s.deleteWatch(w, r)
+		default:
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	}
+}
+
+// listWatches returns every watch with its last check.
+func (s *server) listWatches(w http.ResponseWriter, r *http.Request) {
+	watches, err := s.db.Watches(r.Context())
+	if err != nil {
+		log.Printf("Failed to load watches: %v", err)
+		writeError(w, r, http.StatusInternalServerError, "Failed to load watches")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, watches)
+}
+
+// createWatch adds a watch, due immediately.
+func (s *server) createWatch(w http.ResponseWriter, r *http.Request) {
+	var req watchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	st, ok := s.registry.Get(req.Store)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, "Please provide a valid store")
+		return
+	}
+	if req.ID == "" {
+		writeInvalidInput(w, r, st.Name(), "Please provide an app id")
+		return
+	}
+
+	interval := time.Duration(req.Interval)
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+	if interval < minWatchInterval {
+		writeError(w, r, http.StatusBadRequest, fmt.Sprintf("Interval must be at least %v", minWatchInterval))
+		return
+	}
+
+	if req.ReviewAlerts != nil {
+		if message := validateReviewAlerts(st, req.ReviewAlerts); message != "" {
+			writeError(w, r, http.StatusBadRequest, message)
+			return
+		}
+	}
+
+	q := storeQuery(st, req.ID, req.Lang, req.Country)
+	now := time.Now().UTC()
+	watch, err := s.db.CreateWatch(r.Context(), storage.Watch{
+		Store:        st.Name(),
+		AppID:        q.AppID,
+		BundleID:     q.BundleID,
+		Lang:         q.Lang,
+		Country:      q.Country,
+		Interval:     storage.Duration(interval),
+		ReviewAlerts: req.ReviewAlerts,
+		CreatedAt:    now,
+		NextCheck:    now,
+	})
+	if errors.Is(err, storage.ErrWatchExists) {
+		writeError(w, r, http.StatusConflict, "App is already watched")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create watch: %v", err)
+		writeError(w, r, http.StatusInternalServerError, "Failed to create watch")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, watch)
+}
+
+// deleteWatch removes the watch given by the id query parameter.
+func (s *server) deleteWatch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Please provide a watch id")
+		return
+	}
+
+	err = s.db.DeleteWatch(r.Context(), id)
+	if errors.Is(err, storage.ErrWatchNotFound) {
+		writeError(w, r, http.StatusNotFound, "Watch not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete watch: %v", err)
+		writeError(w, r, http.StatusInternalServerError, "Failed to delete watch")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkWatch refreshes a watched app through the cache, so a check also keeps
+// the cache warm and records a snapshot.
+func (s *server) checkWatch(ctx context.Context, w storage.Watch) (store.App, error) {
+	st, ok := s.registry.Get(w.Store)
+	if !ok {
+		return store.App{}, fmt.Errorf("unknown store %q", w.Store)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.maxTimeout)
+	defer cancel()
+
+	entry, _, err := s.lookup(ctx, st, w.Query(), true)
+	if err != nil {
+		return store.App{}, err
+	}
+
+	s.checkReviewAlerts(ctx, st, w, entry.App)
+	return entry.App, nil
+}
+
+// validateReviewAlerts returns the error message of invalid review alerts, or
+// "" when they are valid. Blank keywords are dropped.
+func validateReviewAlerts(st store.Store, alerts *storage.ReviewAlerts) string {
+	if _, ok := st.(store.ReviewLister); !ok {
+		return st.Title() + " does not support reviews"
+	}
+	if alerts.MaxRating < 0 || alerts.MaxRating > store.MaxRating {
+		return fmt.Sprintf("Review alert maxRating must be between 1 and %d", store.MaxRating)
+	}
+
+	keywords := alerts.Keywords[:0]
+	for _, keyword := range alerts.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	alerts.Keywords = keywords
+
+	if alerts.MaxRating == 0 && len(alerts.Keywords) == 0 {
+		return "Please provide a maxRating or keywords for review alerts"
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+func TestWatchesHandler(t *testing.T) {
+	fake := &fakeStore{app: store.App{AppID: "1", Title: "Example", Version: "1.0"}}
+	srv := newTestServer(t, fake, &fakeReviewStore{})
+	mux := srv.routes()
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do(http.MethodPost, "/watches", `{"store":"fake","id":"1","country":"us","interval":"30m"}`)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created storage.Watch
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&created))
+	assert.Equal(t, "1", created.AppID)
+	assert.Equal(t, storage.Duration(30*time.Minute), created.Interval)
+
+	rr = do(http.MethodPost, "/watches", `{"store":"fake","id":"1","country":"us"}`)
+	checkResponse(t, rr, http.StatusConflict, map[string]string{"code": codeConflict, "message": "App is already watched"})
+
+	rr = do(http.MethodPost, "/watches", `{"store":"reviewed","id":"42","reviewAlerts":{"maxRating":2,"keywords":["crash",""]}}`)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var reviewed storage.Watch
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&reviewed))
+	assert.Equal(t, &storage.ReviewAlerts{Keywords: []string{"crash"}, MaxRating: 2}, reviewed.ReviewAlerts)
+	require.NoError(t, srv.db.DeleteWatch(context.Background(), reviewed.ID))
+
+	// The scheduler checks the new watch right away
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		srv.watcher.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		w, err := srv.db.Watch(context.Background(), created.ID)
+		return err == nil && w.LastChecked != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	rr = do(http.MethodGet, "/watches", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var watches []storage.Watch
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&watches))
+	require.Len(t, watches, 1)
+	require.NotNil(t, watches[0].LastResult)
+	assert.Equal(t, "1.0", watches[0].LastResult.Version)
+	assert.Empty(t, watches[0].LastError)
+	assert.True(t, watches[0].NextCheck.After(*watches[0].LastChecked))
+
+	rr = do(http.MethodDelete, "/watches?id=1", "")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = do(http.MethodDelete, "/watches?id=1", "")
+	checkResponse(t, rr, http.StatusNotFound, map[string]string{"code": codeNotFound, "message": "Watch not found"})
+}
+
+func TestWatchesHandlerValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:           "Invalid body",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid request body"},
+		},
+		{
+			name:           "Unknown store",
+			body:           `{"store":"unknown","id":"1"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"code": codeInvalidInput, "message": "Please provide a valid store"},
+		},
+		{
+			name:           "Missing id",
+			body:           `{"store":"fake"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Please provide an app id"},
+		},
+		{
+			name:           "Interval too short",
+			body:           `{"store":"fake","id":"1","interval":"10s"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Interval must be at least 1m0s"},
+		},
+		{
+			name:           "Review alerts without reviews",
+			body:           `{"store":"fake","id":"1","reviewAlerts":{"maxRating":2}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Fake Store does not support reviews"},
+		},
+		{
+			name:           "Review alerts rating out of range",
+			body:           `{"store":"reviewed","id":"1","reviewAlerts":{"maxRating":6}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Review alert maxRating must be between 1 and 5"},
+		},
+		{
+			name:           "Empty review alerts",
+			body:           `{"store":"reviewed","id":"1","reviewAlerts":{"keywords":[" "]}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Please provide a maxRating or keywords for review alerts"},
+		},
+	}
+
+	srv := newTestServer(t, &fakeStore{}, &fakeReviewStore{})
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/watches", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			srv.routes().ServeHTTP(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+func TestStoreQuery(t *testing.T) {
+	assert.Equal(t, store.Query{AppID: "1", Country: "us"}, storeQuery(store.AppStore{}, "1", "", "us"))
+	assert.Equal(t, store.Query{BundleID: "com.example"}, storeQuery(store.AppStore{}, "com.example", "", ""))
+	assert.Equal(t, store.Query{BundleID: "com.example", Lang: "en"}, storeQuery(store.PlayStore{}, "com.example", "en", ""))
+	assert.Equal(t, store.Query{AppID: "C100"}, storeQuery(store.AppGallery{}, "C100", "", ""))
+}
@@ -0,0 +1,226 @@
+// Package webhook delivers app change events to HTTP receivers.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body, as "sha256=<hex>".
+const SignatureHeader = "X-Katsini-Signature"
+
+// Event types.
+const (
+	// EventAppUpdated is sent when a watched app changes.
+	EventAppUpdated = "app.updated"
+	// EventReviewAlert is sent when a new review of a watched app matches its review alerts.
+	EventReviewAlert = "review.alert"
+)
+
+// Event is the JSON body posted to webhook receivers. Review alerts carry the
+// current app as New, without Old.
+type Event struct {
+	DetectedAt time.Time     `json:"detectedAt"`
+	Review     *store.Review `json:"review,omitempty"`
+	Type       string        `json:"type"`
+	Store      string        `json:"store"`
+	// Reasons lists why a review alerted: "rating" or "keyword:<keyword>"
+	Reasons []string  `json:"reasons,omitempty"`
+	Old     store.App `json:"old,omitzero"`
+	New     store.App `json:"new"`
+	WatchID int64     `json:"watchId"`
+}
+
+// Config configures a Dispatcher.
+type Config struct {
+	// Client sends the requests; defaults to a client with a 10 second timeout.
+	Client *http.Client
+	// Secret signs every payload; payloads are unsigned when empty.
+	Secret string
+	// URLs receive every event.
+	URLs []string
+	// Backoff is the wait before the first retry, doubled after every attempt.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between two attempts.
+	MaxBackoff time.Duration
+	// MaxAttempts is how often a delivery is tried before it is dead-lettered.
+	MaxAttempts int
+}
+
+// Dispatcher posts events to the configured URLs in the background, retrying
+// failed deliveries and recording those that never succeed as dead letters.
+type Dispatcher struct {
+	db *storage.DB
+	// ctx is canceled by Close to stop waiting for retries; attempts in
+	// flight still complete within the client timeout
+	ctx    context.Context
+	cancel context.CancelFunc
+	cfg    Config
+	wg     sync.WaitGroup
+}
+
+// New creates a dispatcher recording dead letters in db.
+func New(db *storage.DB, cfg Config) *Dispatcher {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{db: db, ctx: ctx, cancel: cancel, cfg: cfg}
+}
+
+// Notify delivers an event to every URL without blocking.
+func (d *Dispatcher) Notify(event Event) {
+	if len(d.cfg.URLs) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode webhook event: %v", err)
+		return
+	}
+
+	for _, url := range d.cfg.URLs {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.deliver(url, payload)
+		}()
+	}
+}
+
+// Wait blocks until every delivery succeeded or was dead-lettered.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Close waits for the attempts in flight and dead-letters deliveries waiting for a retry.
+func (d *Dispatcher) Close() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// deliver posts a payload until it is accepted or the attempts run out.
+func (d *Dispatcher) deliver(url string, payload []byte) {
+	var lastErr error
+	attempts := 0
+	for attempts < d.cfg.MaxAttempts {
+		if attempts > 0 {
+			if err := sleepContext(d.ctx, d.backoff(attempts)); err != nil {
+				break
+			}
+		}
+
+		attempts++
+		lastErr = d.post(url, payload)
+		if lastErr == nil {
+			return
+		}
+		log.Printf("Webhook delivery to %s failed (attempt %d/%d): %v", url, attempts, d.cfg.MaxAttempts, lastErr)
+	}
+
+	// Dead letters are recorded even while shutting down, so no event is lost silently
+	err := d.db.RecordDeadLetter(context.WithoutCancel(d.ctx), storage.DeadLetter{
+		URL:       url,
+		Payload:   payload,
+		Attempts:  attempts,
+		LastError: lastErr.Error(),
+		FailedAt:  time.Now(),
+	})
+	if err != nil {
+		log.Printf("Failed to record dead letter: %v", err)
+	}
+}
+
+// backoff returns the wait before the given retry.
+func (d *Dispatcher) backoff(retry int) time.Duration {
+	wait := d.cfg.Backoff
+	for i := 1; i < retry && wait < d.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.cfg.MaxBackoff)
+}
+
+// post sends a single delivery attempt; any non-2xx status is a failure.
+func (d *Dispatcher) post(url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(context.WithoutCancel(d.ctx), http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.cfg.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(d.cfg.Secret, payload))
+	}
+
+	resp, err := d.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value of payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid signature header value of payload.
+func Verify(secret string, payload []byte, signature string) bool {
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sum)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// sleepContext waits for d, returning early with the context error when ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+)
+
+// openTestDB opens an empty database in a temporary directory
+func openTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+
+	db, err := storage.Open(context.Background(), filepath.Join(t.TempDir(), "katsini.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+var testEvent = Event{
+	Type:       EventAppUpdated,
+	Store:      "appstore",
+	WatchID:    1,
+	Old:        store.App{AppID: "1", Version: "1.0"},
+	New:        store.App{AppID: "1", Version: "1.1"},
+	DetectedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestDispatcherDeliversSignedEvents(t *testing.T) {
+	received := make(chan Event, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.True(t, Verify("secret", body, r.Header.Get(SignatureHeader)), "signature must verify")
+
+		var event Event
+		assert.NoError(t, json.Unmarshal(body, &event))
+		received <- event
+	}))
+	defer receiver.Close()
+
+	db := openTestDB(t)
+	d := New(db, Config{URLs: []string{receiver.URL}, Secret: "secret"})
+	d.Notify(testEvent)
+	d.Close()
+
+	assert.Equal(t, testEvent, <-received)
+	letters, err := db.DeadLetters(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	db := openTestDB(t)
+	d := New(db, Config{URLs: []string{receiver.URL}, Backoff: time.Millisecond, MaxAttempts: 3})
+	d.Notify(testEvent)
+	d.Wait()
+
+	assert.Equal(t, int32(3), calls.Load())
+	letters, err := db.DeadLetters(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestDispatcherDeadLetters(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	db := openTestDB(t)
+	d := New(db, Config{URLs: []string{receiver.URL}, Backoff: time.Millisecond, MaxAttempts: 2})
+	d.Notify(testEvent)
+	d.Wait()
+
+	assert.Equal(t, int32(2), calls.Load())
+	letters, err := db.DeadLetters(context.Background())
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, receiver.URL, letters[0].URL)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Equal(t, "unexpected status 500", letters[0].LastError)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(letters[0].Payload, &event))
+	assert.Equal(t, testEvent, event)
+}
+
+func TestDispatcherCloseDeadLettersPendingRetries(t *testing.T) {
+	attempted := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		close(attempted)
+	}))
+	defer receiver.Close()
+
+	db := openTestDB(t)
+	d := New(db, Config{URLs: []string{receiver.URL}, Backoff: time.Hour, MaxAttempts: 5})
+	d.Notify(testEvent)
+
+	// The retry waits an hour; closing must not wait for it
+	<-attempted
+	d.Close()
+
+	letters, err := db.DeadLetters(context.Background())
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, 1, letters[0].Attempts)
+}
+
+func TestBackoff(t *testing.T) {
+	d := New(nil, Config{Backoff: time.Second, MaxBackoff: 10 * time.Second})
+	assert.Equal(t, time.Second, d.backoff(1))
+	assert.Equal(t, 2*time.Second, d.backoff(2))
+	assert.Equal(t, 4*time.Second, d.backoff(3))
+	assert.Equal(t, 10*time.Second, d.backoff(5))
+}
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{"type":"app.updated"}`)
+	signature := Sign("secret", payload)
+
+	assert.True(t, Verify("secret", payload, signature))
+	assert.False(t, Verify("other", payload, signature))
+	assert.False(t, Verify("secret", []byte(`{}`), signature))
+	assert.False(t, Verify("secret", payload, "sha1=abc"))
+	assert.False(t, Verify("secret", payload, "sha256=zz"))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+	"github.com/arisecode/katsini/webhook"
+)
+
+// notifyChange sends a webhook event for a watched app whose version or update date changed.
+func (s *server) notifyChange(w storage.Watch, old, updated store.App, detectedAt time.Time) {
+	log.Printf("Watch %d on %s changed from %s to %s", w.ID, w.Store, old.Version, updated.Version)
+	s.webhooks.Notify(webhook.Event{
+		Type:       webhook.EventAppUpdated,
+		Store:      w.Store,
+		WatchID:    w.ID,
+		Old:        old,
+		New:        updated,
+		DetectedAt: detectedAt,
+	})
+}
+
+// handleDeadLetters lists the webhook deliveries that failed after every retry.
+func (s *server) handleDeadLetters() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		letters, err := s.db.DeadLetters(r.Context())
+		if err != nil {
+			log.Printf("Failed to load dead letters: %v", err)
+			writeError(w, r, http.StatusInternalServerError, "Failed to load dead letters")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, letters)
+	}
+}
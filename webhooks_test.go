@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arisecode/katsini/storage"
+	"github.com/arisecode/katsini/store"
+	"github.com/arisecode/katsini/webhook"
+)
+
+func TestNotifyChange(t *testing.T) {
+	received := make(chan webhook.Event, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.True(t, webhook.Verify("secret", body, r.Header.Get(webhook.SignatureHeader)))
+
+		var event webhook.Event
+		assert.NoError(t, json.Unmarshal(body, &event))
+		received <- event
+	}))
+	defer receiver.Close()
+
+	srv := newTestServer(t, &fakeStore{})
+	srv.webhooks = webhook.New(srv.db, webhook.Config{URLs: []string{receiver.URL}, Secret: "secret"})
+
+	now := time.Now().UTC()
+	srv.notifyChange(storage.Watch{ID: 1, Store: "fake"}, store.App{Version: "1.0"}, store.App{Version: "1.1"}, now)
+	srv.webhooks.Close()
+
+	event := <-received
+	assert.Equal(t, webhook.EventAppUpdated, event.Type)
+	assert.Equal(t, "fake", event.Store)
+	assert.Equal(t, "1.0", event.Old.Version)
+	assert.Equal(t, "1.1", event.New.Version)
+}
+
+func TestDeadLettersHandler(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	srv := newTestServer(t, &fakeStore{})
+	srv.webhooks = webhook.New(srv.db, webhook.Config{URLs: []string{receiver.URL}, Backoff: time.Millisecond, MaxAttempts: 2})
+	srv.notifyChange(storage.Watch{ID: 1, Store: "fake"}, store.App{Version: "1.0"}, store.App{Version: "1.1"}, time.Now())
+	srv.webhooks.Wait()
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/dead-letters", http.NoBody)
+	rr := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var letters []storage.DeadLetter
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&letters))
+	require.Len(t, letters, 1)
+	assert.Equal(t, receiver.URL, letters[0].URL)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Equal(t, "unexpected status 500", letters[0].LastError)
+}